@@ -3,56 +3,87 @@ package main
 import (
 	"flag"
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/robertcurry0216/cross/internal/config"
 	"github.com/robertcurry0216/cross/internal/model"
 	puz "github.com/robertcurry0216/cross/internal/puzzle"
-	"github.com/robertcurry0216/cross/internal/screen"
+	"github.com/robertcurry0216/cross/internal/theme"
 )
 
-func buildAndValidatePuzzle(path string) (*puz.Puzzle, error) {
-	builder, err := puz.NewBuilderFromFile(path)
-	if err != nil {
-		return &puz.Puzzle{}, err
-	}
-
-	p, err := builder.Build()
+func main() {
+	configPath, err := config.Path()
 	if err != nil {
-		return &puz.Puzzle{}, err
+		fmt.Println("Error:", err)
+		os.Exit(1)
 	}
 
-	if err := builder.Validate(); err != nil {
-		return &puz.Puzzle{}, err
+	flag.StringVar(&configPath, "config", configPath, "path to the config file")
+	library := flag.String("library", "", fmt.Sprintf("directories to look for puzzles in, separated by %q", os.PathListSeparator))
+	autosave := flag.Bool("autosave", true, "save the puzzle after every change")
+	ascii := flag.Bool("ascii", false, "draw the grid and borders in ASCII")
+	themeName := flag.String("theme", "", fmt.Sprintf("the theme to draw with, one of %s or a theme file's name", strings.Join(theme.Names(), ", ")))
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: cross [flags] [crossword_file.puz]")
+		flag.PrintDefaults()
 	}
-	return p, nil
-}
-
-func main() {
 	flag.Parse()
 
-	args := flag.Args()
-	if len(args) < 1 {
-		fmt.Println("Error: Please provide a crossword file path")
-		fmt.Println("Usage: cross [crossword_file.puz]")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
 		os.Exit(1)
 	}
 
-	path := args[0]
-	p, err := buildAndValidatePuzzle(path)
+	// flags given on the command line win over the config file
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "library":
+			cfg.Library.Dirs = filepath.SplitList(*library)
+		case "autosave":
+			cfg.General.Autosave = *autosave
+		case "theme":
+			cfg.Display.Theme = *themeName
+		case "ascii":
+			cfg.Display.Charset = config.CharsetUnicode
+			if *ascii {
+				cfg.Display.Charset = config.CharsetASCII
+			}
+		}
+	})
+
+	th, err := theme.Load(cfg, filepath.Join(filepath.Dir(configPath), "themes"))
 	if err != nil {
-		fmt.Printf("Error loading puzzle: %v\n", err)
+		fmt.Printf("Error loading theme: %v\n", err)
 		os.Exit(1)
 	}
+	// without colors the highlights are drawn in reverse video and bold,
+	// which still need the terminal's escape codes
+	if th.Mono && lipgloss.ColorProfile() == termenv.Ascii {
+		lipgloss.SetColorProfile(termenv.ANSI)
+	}
 
-	// return
-	m := model.NewModel()
+	m := model.NewModel(cfg, th)
 
-	model.SetPuzzle(&m, p)
-	m.PushView(&screen.PuzzleScreen{})
+	args := flag.Args()
+	if len(args) < 1 {
+		model.OpenLibrary(&m, cfg.Library.Dirs)
+	} else {
+		p, err := puz.LoadFromFile(args[0])
+		if err != nil {
+			fmt.Printf("Error loading puzzle: %v\n", err)
+			os.Exit(1)
+		}
+		model.OpenPuzzle(&m, p)
+	}
 
-	if _, err := tea.NewProgram(m).Run(); err != nil {
+	if _, err := tea.NewProgram(m, tea.WithMouseCellMotion()).Run(); err != nil {
 		fmt.Println("Error running program:", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,43 @@
+package common
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FuzzyMatch reports whether the letters of the query appear in order in the
+// text, ignoring case. Matches score higher the more of the query falls at
+// the start of words or in runs of consecutive letters.
+func FuzzyMatch(query, text string) (int, bool) {
+	q := []rune(strings.ToLower(strings.TrimSpace(query)))
+	t := []rune(strings.ToLower(text))
+	if len(q) == 0 {
+		return 0, true
+	}
+
+	score, qi := 0, 0
+	prevMatch := -2
+	for ti, r := range t {
+		// spaces in the query only separate words
+		for qi < len(q) && unicode.IsSpace(q[qi]) {
+			qi++
+		}
+		if qi == len(q) {
+			break
+		}
+		if r != q[qi] {
+			continue
+		}
+
+		score++
+		if ti == prevMatch+1 {
+			score += 2
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 3
+		}
+		prevMatch = ti
+		qi++
+	}
+	return score, qi == len(q)
+}
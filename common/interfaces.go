@@ -4,3 +4,9 @@ type Viewable interface {
 	View(state State) string
 	Init(state State)
 }
+
+// InputCapturer is a Viewable that takes typed text, so wants keys that would
+// otherwise be actions.
+type InputCapturer interface {
+	CapturesInput() bool
+}
@@ -1,16 +1,36 @@
 package common
 
-import "github.com/robertcurry0216/cross/internal/puzzle"
+import (
+	"github.com/robertcurry0216/cross/internal/config"
+	"github.com/robertcurry0216/cross/internal/keymap"
+	"github.com/robertcurry0216/cross/internal/puzzle"
+	"github.com/robertcurry0216/cross/internal/theme"
+)
 
 // the State for the cli
 type State struct {
 	Debug  string
+	Config config.Config
+	Keys   keymap.KeyMap
+	Theme  *theme.Theme
 	Puzzle *puzzle.Puzzle
 	Views  []Viewable
 	Width  int
 	Height int
 
-	PuzzleView PuzzleView
+	PuzzleView  PuzzleView
+	LibraryView LibraryView
+}
+
+// InputCaptured reports whether a text field is open and wants every key,
+// including the ones that are handled globally.
+func (s State) InputCaptured() bool {
+	if len(s.Views) > 0 {
+		if view, ok := s.Views[len(s.Views)-1].(InputCapturer); ok && view.CapturesInput() {
+			return true
+		}
+	}
+	return s.PuzzleView.EditingNote || s.LibraryView.Filtering
 }
 
 type LayoutType int
@@ -25,9 +45,51 @@ type PuzzleView struct {
 	Y      int
 	IsVert bool
 	Layout LayoutType
+
+	// note editor for the selected clue
+	EditingNote bool
+	NoteDraft   string
+
+	// modal input, when the vim input mode is on
+	Vim VimState
+
+	// result of the last action, shown until the next key
+	Message string
+
+	// leave filled answers out of the clue panel
+	UnfilledOnly bool
+
+	// lines the clue panel is scrolled by with the mouse wheel, from where
+	// it centers the selected clue
+	ClueScroll int
+}
+
+type VimMode int
+
+const (
+	VimNormal VimMode = iota
+	VimInsert
+	VimCommand
+)
+
+type VimState struct {
+	Mode    VimMode
+	Count   int    // count typed before a motion
+	Pending string // first key of a two key command, like g in gg
+	Command string // the : command being typed
+}
+
+type LibraryView struct {
+	Selected  int
+	Sort      puzzle.LibrarySort
+	Filter    string
+	Filtering bool
 }
 
+// LayoutBox is a box's size, and where its top left corner is on the screen.
 type LayoutBox struct {
+	X int
+	Y int
 	W int
 	H int
 }
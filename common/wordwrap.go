@@ -2,6 +2,8 @@ package common
 
 import (
 	"bytes"
+	"regexp"
+	"strings"
 	"unicode"
 )
 
@@ -15,6 +17,10 @@ const nbsp = 0xA0
 // version of the library will implement smarter wrapping. This means that
 // pathological cases can dramatically reach past the limit, such as a very
 // long word.
+//
+// ANSI escape sequences take up no width and are never split. Styles that
+// are still active at the end of a line are reset there and reopened at the
+// start of the next line, so every line can be rendered on its own.
 func WrapString(s string, lim uint) string {
 	// Initialize a buffer with a slightly larger size to account for breaks
 	init := make([]byte, 0, len(s))
@@ -24,8 +30,14 @@ func WrapString(s string, lim uint) string {
 	var wordBuf, spaceBuf bytes.Buffer
 	var wordBufLen, spaceBufLen uint
 
+	var escState int
+
 	for _, char := range s {
-		if char == '\n' {
+		if escState != escNone || char == esc {
+			// escape sequences are zero width and stay with the next word
+			wordBuf.WriteRune(char)
+			escState = nextEscState(escState, char)
+		} else if char == '\n' {
 			if wordBuf.Len() == 0 {
 				if current+spaceBufLen > lim {
 					current = 0
@@ -81,5 +93,59 @@ func WrapString(s string, lim uint) string {
 		wordBuf.WriteTo(buf)
 	}
 
-	return buf.String()
+	return reopenStyles(buf.String())
+}
+
+const esc = '\x1b'
+
+const (
+	escNone = iota
+	escStart
+	escCSI
+)
+
+// nextEscState steps through an escape sequence one rune at a time.
+func nextEscState(state int, char rune) int {
+	switch state {
+	case escNone:
+		return escStart
+	case escStart:
+		if char == '[' {
+			return escCSI
+		}
+		return escNone
+	case escCSI:
+		if char >= 0x40 && char <= 0x7e {
+			return escNone
+		}
+		return escCSI
+	}
+	return escNone
 }
+
+// reopenStyles resets any SGR styling at the end of each line and restores it
+// at the start of the following one.
+func reopenStyles(s string) string {
+	if !strings.ContainsRune(s, esc) {
+		return s
+	}
+
+	lines := strings.Split(s, "\n")
+	var active string
+	for i, line := range lines {
+		lines[i] = active + line
+		for _, seq := range sgrPattern.FindAllString(line, -1) {
+			if seq == "\x1b[m" || seq == "\x1b[0m" {
+				active = ""
+			} else {
+				active += seq
+			}
+		}
+		if active != "" {
+			lines[i] += "\x1b[0m"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+var sgrPattern = regexp.MustCompile("\x1b\\[[0-9;:]*m")
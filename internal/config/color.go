@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Color is a terminal color with variants for light and dark backgrounds. In
+// the config it is either a single value or a table with light and dark keys,
+// either of which can be left out. Values are ANSI color numbers or hex codes.
+type Color struct {
+	Light string
+	Dark  string
+}
+
+var hexColorRe = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+func (c *Color) UnmarshalTOML(value any) error {
+	switch value := value.(type) {
+	case string:
+		c.Light, c.Dark = value, value
+	case int64:
+		c.Light = strconv.FormatInt(value, 10)
+		c.Dark = c.Light
+	case map[string]any:
+		*c = Color{}
+		for key, v := range value {
+			s := fmt.Sprint(v)
+			switch key {
+			case "light":
+				c.Light = s
+			case "dark":
+				c.Dark = s
+			default:
+				return fmt.Errorf("unknown color variant %q, expected light or dark", key)
+			}
+		}
+	default:
+		return fmt.Errorf("expected a color, got %v", value)
+	}
+
+	if !c.IsSet() {
+		return fmt.Errorf("expected a light or dark color")
+	}
+	for _, s := range []string{c.Light, c.Dark} {
+		if s != "" && !isValidColor(s) {
+			return fmt.Errorf("invalid color %q, expected an ANSI color number or a hex code", s)
+		}
+	}
+	return nil
+}
+
+func isValidColor(s string) bool {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n >= 0 && n <= 255
+	}
+	return hexColorRe.MatchString(s)
+}
+
+// IsSet reports whether the color was given.
+func (c Color) IsSet() bool {
+	return c.Light != "" || c.Dark != ""
+}
+
+// Over returns the color with a variant it leaves out taken from base.
+func (c Color) Over(base Color) Color {
+	if c.Light == "" {
+		c.Light = base.Light
+	}
+	if c.Dark == "" {
+		c.Dark = base.Dark
+	}
+	return c
+}
+
+// Adaptive returns the color for lipgloss. A variant left out is the same as
+// the other.
+func (c Color) Adaptive() lipgloss.AdaptiveColor {
+	c = c.Over(Color{Light: c.Dark, Dark: c.Light})
+	return lipgloss.AdaptiveColor{Light: c.Light, Dark: c.Dark}
+}
@@ -0,0 +1,375 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/robertcurry0216/cross/internal/keymap"
+)
+
+// Config holds the user's settings, read from
+// $XDG_CONFIG_HOME/cross/config.toml.
+type Config struct {
+	General GeneralConfig `toml:"general"`
+	Library LibraryConfig `toml:"library"`
+	Typing  TypingConfig  `toml:"typing"`
+	Display DisplayConfig `toml:"display"`
+	Colors  ColorsConfig  `toml:"colors"`
+	Keys    KeysConfig    `toml:"keys"`
+}
+
+type GeneralConfig struct {
+	// save the grid after every change, rather than only on exit
+	Autosave bool `toml:"autosave"`
+	// how keys are handled in the grid, "standard" or "vim"
+	Input InputMode `toml:"input"`
+}
+
+type InputMode string
+
+const (
+	InputStandard InputMode = "standard"
+	InputVim      InputMode = "vim"
+)
+
+func (i *InputMode) UnmarshalText(text []byte) error {
+	switch mode := InputMode(text); mode {
+	case InputStandard, InputVim:
+		*i = mode
+		return nil
+	}
+	return fmt.Errorf("unknown input mode %q, expected %q or %q", text, InputStandard, InputVim)
+}
+
+type DisplayConfig struct {
+	// how the grid is drawn, "auto" picks the largest that fits
+	Grid GridMode `toml:"grid"`
+	// where the clues go around the grid, "auto" picks by the window size
+	Layout LayoutMode `toml:"layout"`
+	// show the current and crossing clues above the grid
+	ClueBar bool `toml:"clue_bar"`
+	// a built in theme or one in the themes directory beside the config
+	Theme string `toml:"theme"`
+	// the characters the grid and borders are drawn with, "auto" picks ASCII
+	// when the locale isn't UTF-8
+	Charset Charset `toml:"charset"`
+}
+
+type Charset string
+
+const (
+	CharsetAuto    Charset = "auto"
+	CharsetUnicode Charset = "unicode"
+	CharsetASCII   Charset = "ascii"
+)
+
+func (c *Charset) UnmarshalText(text []byte) error {
+	switch charset := Charset(text); charset {
+	case CharsetAuto, CharsetUnicode, CharsetASCII:
+		*c = charset
+		return nil
+	}
+	return fmt.Errorf("unknown charset %q, expected %q, %q or %q", text, CharsetAuto, CharsetUnicode, CharsetASCII)
+}
+
+type LayoutMode string
+
+const (
+	LayoutAuto LayoutMode = "auto"
+	// clues in a column beside the grid
+	LayoutSide LayoutMode = "side"
+	// clues under the grid
+	LayoutStacked LayoutMode = "stacked"
+	// Across and Down in columns of their own beside the grid
+	LayoutColumns LayoutMode = "columns"
+	// only the current clue, in a bar above the grid
+	LayoutBar LayoutMode = "bar"
+	// only the grid
+	LayoutZen LayoutMode = "zen"
+)
+
+func (l *LayoutMode) UnmarshalText(text []byte) error {
+	switch mode := LayoutMode(text); mode {
+	case LayoutAuto, LayoutSide, LayoutStacked, LayoutColumns, LayoutBar, LayoutZen:
+		*l = mode
+		return nil
+	}
+	return fmt.Errorf("unknown layout %q, expected one of auto, side, stacked, columns, bar or zen", text)
+}
+
+type GridMode string
+
+const (
+	GridAuto    GridMode = "auto"
+	GridFull    GridMode = "full"
+	GridCompact GridMode = "compact"
+)
+
+func (g *GridMode) UnmarshalText(text []byte) error {
+	switch mode := GridMode(text); mode {
+	case GridAuto, GridFull, GridCompact:
+		*g = mode
+		return nil
+	}
+	return fmt.Errorf("unknown grid mode %q, expected %q, %q or %q", text, GridAuto, GridFull, GridCompact)
+}
+
+// TypingConfig sets where the cursor goes after typing a letter and moving
+// with the arrows. Each option is off unless turned on.
+type TypingConfig struct {
+	// pass over letters already in the answer
+	SkipFilled bool `toml:"skip_filled"`
+	// move to the next unfinished answer from the end of one
+	JumpToNextClue bool `toml:"jump_to_next_clue"`
+	// carry on from the last Across clue to the first Down, and back
+	WrapDirections bool `toml:"wrap_directions"`
+	// an arrow across the current direction turns before it moves
+	ArrowChangesDirection bool `toml:"arrow_changes_direction"`
+	// next and previous clue pass over answers that are filled in
+	NextClueUnfilled bool `toml:"next_clue_unfilled"`
+}
+
+type LibraryConfig struct {
+	Dirs []string `toml:"dirs"`
+}
+
+// KeysConfig rebinds actions, by name, to one key or a list of keys.
+type KeysConfig map[string]KeyList
+
+// ColorsConfig sets colors over the theme's. Colors left out keep the
+// theme's, as do the light or dark variants of a color left out.
+type ColorsConfig struct {
+	HighlightBG   Color `toml:"highlight_bg"`
+	HighlightFG   Color `toml:"highlight_fg"`
+	Error         Color `toml:"error"`
+	Correct       Color `toml:"correct"`
+	StatusBar     Color `toml:"status_bar"`
+	FocusedBorder Color `toml:"focused_border"`
+	WordBreak     Color `toml:"word_break"`
+	Shaded        Color `toml:"shaded"`
+	GridLine      Color `toml:"grid_line"`
+}
+
+// Merge returns the colors with the ones set in over in place of its own.
+func (c ColorsConfig) Merge(over ColorsConfig) ColorsConfig {
+	merged := c
+	dst, src := reflect.ValueOf(&merged).Elem(), reflect.ValueOf(over)
+	for i := 0; i < src.NumField(); i++ {
+		if color := src.Field(i).Interface().(Color); color.IsSet() {
+			dst.Field(i).Set(reflect.ValueOf(color.Over(dst.Field(i).Interface().(Color))))
+		}
+	}
+	return merged
+}
+
+// Default returns the settings used when there is no config file.
+func Default() Config {
+	return Config{
+		General: GeneralConfig{
+			Autosave: true,
+			Input:    InputStandard,
+		},
+		Library: LibraryConfig{
+			Dirs: []string{"."},
+		},
+		Display: DisplayConfig{
+			Grid:    GridAuto,
+			Layout:  LayoutAuto,
+			ClueBar: true,
+			Theme:   "default",
+			Charset: CharsetAuto,
+		},
+	}
+}
+
+// Dir returns the directory the config file is kept in,
+// $XDG_CONFIG_HOME/cross.
+func Dir() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find config directory: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "cross"), nil
+}
+
+// Path returns the default config file path.
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.toml"), nil
+}
+
+// Load reads the config file at path over the defaults. A missing file is
+// not an error.
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	} else if err != nil {
+		return cfg, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := Parse(&cfg, string(raw)); err != nil {
+		var configErr *Error
+		if errors.As(err, &configErr) {
+			configErr.Path = path
+		}
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// Parse decodes the TOML into cfg, keeping the values of anything the TOML
+// leaves out. Problems are reported as an *Error.
+func Parse(cfg *Config, data string) error {
+	md, err := toml.Decode(data, cfg)
+	if err != nil {
+		return newDecodeError(err)
+	}
+
+	if key, ok := unknownKey(md); ok {
+		return &Error{Line: keyLine(data, key), Key: key.String(), Msg: "unknown setting"}
+	}
+
+	for action := range cfg.Keys {
+		if !keymap.IsAction(action) {
+			key := toml.Key{"keys", action}
+			return &Error{Line: keyLine(data, key), Key: key.String(), Msg: "unknown action"}
+		}
+	}
+
+	for i, dir := range cfg.Library.Dirs {
+		cfg.Library.Dirs[i] = ExpandHome(dir)
+	}
+	return nil
+}
+
+// unknownKey returns the first key that doesn't match a setting. Keys inside
+// values that decode themselves, like colors, are left to the value.
+func unknownKey(md toml.MetaData) (toml.Key, bool) {
+	tables := configTables(reflect.TypeOf(Config{}), "")
+	for _, key := range md.Undecoded() {
+		if tables[toml.Key(key[:len(key)-1]).String()] {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// configTables returns the TOML tables of the struct type, by key, including
+// the top level.
+func configTables(t reflect.Type, prefix string) map[string]bool {
+	tables := map[string]bool{prefix: true}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Type.Kind() != reflect.Struct || reflect.PointerTo(field.Type).Implements(reflect.TypeOf((*toml.Unmarshaler)(nil)).Elem()) {
+			continue
+		}
+		name := field.Tag.Get("toml")
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		for table := range configTables(field.Type, name) {
+			tables[table] = true
+		}
+	}
+	return tables
+}
+
+// ExpandHome replaces a leading ~ in the path with the user's home
+// directory.
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// Error is a problem with the config file.
+type Error struct {
+	Path string
+	Line int
+	Key  string
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	if e.Path != "" {
+		b.WriteString(e.Path + ":")
+	}
+	if e.Line > 0 {
+		fmt.Fprintf(&b, "%d:", e.Line)
+	}
+	if b.Len() > 0 {
+		b.WriteString(" ")
+	}
+	if e.Key != "" {
+		b.WriteString(e.Key + ": ")
+	}
+	b.WriteString(e.Msg)
+	return b.String()
+}
+
+var decodeErrorRe = regexp.MustCompile(`^toml: line (\d+)(?: \(last key "(.*?)"\))?: (.*)$`)
+
+// newDecodeError pulls the line and key out of the TOML decoder's errors.
+func newDecodeError(err error) error {
+	match := decodeErrorRe.FindStringSubmatch(err.Error())
+	if match == nil {
+		return &Error{Msg: strings.TrimPrefix(err.Error(), "toml: ")}
+	}
+	line, _ := strconv.Atoi(match[1])
+	return &Error{Line: line, Key: match[2], Msg: match[3]}
+}
+
+// keyLine finds the line the key is set on, falling back to its table. The
+// decoder doesn't report positions for keys that aren't used.
+func keyLine(data string, key toml.Key) int {
+	for n := len(key); n > 0; n-- {
+		want := toml.Key(key[:n]).String()
+		table := ""
+		for i, line := range strings.Split(data, "\n") {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "[") {
+				name := strings.Trim(strings.SplitN(line, "#", 2)[0], "[] \t")
+				if name == want {
+					return i + 1
+				}
+				table = name
+				continue
+			}
+			name, _, found := strings.Cut(line, "=")
+			if !found || strings.HasPrefix(line, "#") {
+				continue
+			}
+			name = strings.Trim(strings.TrimSpace(name), `"'`)
+			if table != "" {
+				name = table + "." + name
+			}
+			if name == want {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
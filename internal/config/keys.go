@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+)
+
+// KeyList is the keys bound to an action. In the config it is either a
+// single key or a list of them; an empty list unbinds the action.
+type KeyList []string
+
+func (k *KeyList) UnmarshalTOML(value any) error {
+	switch value := value.(type) {
+	case string:
+		*k = KeyList{value}
+	case []any:
+		keys := make(KeyList, 0, len(value))
+		for _, v := range value {
+			s, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("expected a key name, got %v", v)
+			}
+			keys = append(keys, s)
+		}
+		*k = keys
+	default:
+		return fmt.Errorf("expected a key or a list of keys, got %v", value)
+	}
+
+	for _, key := range *k {
+		if key == "" {
+			return fmt.Errorf("key names can't be empty")
+		}
+	}
+	return nil
+}
+
+// Overrides returns the bindings in the form the keymap takes.
+func (k KeysConfig) Overrides() map[string][]string {
+	overrides := make(map[string][]string, len(k))
+	for action, keys := range k {
+		overrides[action] = keys
+	}
+	return overrides
+}
@@ -0,0 +1,237 @@
+package keymap
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Action is something a key can be bound to. Its value is the name used for
+// it in the [keys] table of the config.
+type Action string
+
+const (
+	Back            Action = "back"
+	Quit            Action = "quit"
+	Help            Action = "help"
+	Up              Action = "up"
+	Down            Action = "down"
+	Left            Action = "left"
+	Right           Action = "right"
+	ToggleDirection Action = "toggle_direction"
+	Delete          Action = "delete"
+	CheckLetter     Action = "check_letter"
+	CheckWord       Action = "check_word"
+	CheckPuzzle     Action = "check_puzzle"
+	RevealWord      Action = "reveal_word"
+	RevealPuzzle    Action = "reveal_puzzle"
+	Note            Action = "note"
+	ByClue          Action = "by_clue"
+	Meta            Action = "meta"
+	Info            Action = "info"
+	ToggleLayout    Action = "toggle_layout"
+	Save            Action = "save"
+	NextUnsolved    Action = "next_unsolved"
+	PrevUnsolved    Action = "prev_unsolved"
+	NextClue        Action = "next_clue"
+	PrevClue        Action = "prev_clue"
+	FilterClues     Action = "filter_clues"
+	Open            Action = "open"
+	Sort            Action = "sort"
+	Filter          Action = "filter"
+	Refresh         Action = "refresh"
+	Palette         Action = "palette"
+	ClearWord       Action = "clear_word"
+	ClearPuzzle     Action = "clear_puzzle"
+	Export          Action = "export"
+)
+
+// actionDefaults holds the default keys and help text of every action, in
+// the order they are listed on the help screen.
+var actionDefaults = []struct {
+	action Action
+	keys   []string
+	help   string
+}{
+	{Back, []string{"esc"}, "Back"},
+	{Quit, []string{"ctrl+c"}, "Quit"},
+	{Help, []string{"?"}, "Help"},
+	{Up, []string{"up"}, "Up"},
+	{Down, []string{"down"}, "Down"},
+	{Left, []string{"left"}, "Left"},
+	{Right, []string{"right"}, "Right"},
+	{ToggleDirection, []string{" "}, "Toggle direction"},
+	{Delete, []string{"backspace"}, "Delete"},
+	{CheckLetter, []string{"ctrl+l"}, "Check letter"},
+	{CheckWord, []string{"ctrl+w"}, "Check word"},
+	{CheckPuzzle, []string{"ctrl+a"}, "Check puzzle"},
+	{RevealWord, []string{"ctrl+r"}, "Reveal word"},
+	{RevealPuzzle, []string{"ctrl+p"}, "Reveal puzzle"},
+	{Note, []string{"ctrl+n"}, "Note"},
+	{ByClue, []string{"ctrl+b"}, "By clue"},
+	{Meta, []string{"ctrl+e"}, "Circled letters"},
+	{Info, []string{"ctrl+g"}, "Info"},
+	{ToggleLayout, []string{"ctrl+f"}, "Toggle focus"},
+	{Save, []string{"ctrl+s"}, "Save"},
+	{NextUnsolved, []string{"tab"}, "Next unsolved"},
+	{PrevUnsolved, []string{"shift+tab"}, "Previous unsolved"},
+	{NextClue, []string{"tab"}, "Next clue"},
+	{PrevClue, []string{"shift+tab"}, "Previous clue"},
+	{FilterClues, []string{"ctrl+u"}, "Unfilled clues only"},
+	{Open, []string{"enter"}, "Open"},
+	{Sort, []string{"s"}, "Sort"},
+	{Filter, []string{"/"}, "Filter"},
+	{Refresh, []string{"r"}, "Refresh"},
+	{Palette, []string{"ctrl+k"}, "Commands"},
+	{ClearWord, nil, "Clear word"},
+	{ClearPuzzle, nil, "Clear puzzle"},
+	{Export, nil, "Export as text"},
+}
+
+// The actions each screen responds to, in the order they are shown in its
+// status bar.
+var (
+	PuzzleActions = []Action{
+		Back, Palette, CheckLetter, CheckWord, CheckPuzzle, RevealWord,
+		RevealPuzzle, ClearWord, ClearPuzzle, Note, ByClue, Meta, Info, Save,
+		Export, NextClue, PrevClue, FilterClues, ToggleLayout, ToggleDirection,
+		Delete, Up, Down, Left, Right, Help,
+	}
+	ClueActions = []Action{
+		NextUnsolved, PrevUnsolved, Up, Down, Left, Right, Delete, ByClue, Back,
+	}
+	LibraryActions = []Action{
+		Open, Filter, Sort, Refresh, Up, Down, Help, Back,
+	}
+	// the actions handled before any screen sees the key
+	GlobalActions = []Action{Back, Quit}
+
+	// the actions listed in the command palette
+	PaletteActions = []Action{
+		CheckLetter, CheckWord, CheckPuzzle, RevealWord, RevealPuzzle,
+		ClearWord, ClearPuzzle, NextClue, PrevClue, NextUnsolved, PrevUnsolved,
+		FilterClues, ToggleDirection, ToggleLayout, Note, ByClue, Meta, Info,
+		Save, Export, Help,
+	}
+
+	// the keys the command palette answers to, besides those typed into it
+	PaletteKeyActions = []Action{Open, Up, Down, Back}
+
+	// the actions shown in the puzzle screen's status bar, as many as fit
+	PuzzleStatusActions = []Action{
+		Back, Palette, CheckLetter, CheckWord, CheckPuzzle, RevealWord, RevealPuzzle,
+		Note, ByClue, Meta, Info,
+	}
+)
+
+// KeyMap holds the key bindings of every action.
+type KeyMap struct {
+	bindings map[Action]key.Binding
+}
+
+// Default returns the built in bindings.
+func Default() KeyMap {
+	return New(nil)
+}
+
+// New returns the default bindings with the keys of some actions replaced.
+// An action given no keys is unbound.
+func New(overrides map[string][]string) KeyMap {
+	bindings := make(map[Action]key.Binding, len(actionDefaults))
+	for _, def := range actionDefaults {
+		keys := def.keys
+		if override, ok := overrides[string(def.action)]; ok {
+			keys = make([]string, len(override))
+			for i, k := range override {
+				keys[i] = k
+				if k == "space" {
+					keys[i] = " "
+				}
+			}
+		}
+		binding := key.NewBinding(key.WithKeys(keys...), key.WithHelp(helpKeys(keys), def.help))
+		if len(keys) == 0 {
+			binding.SetEnabled(false)
+		}
+		bindings[def.action] = binding
+	}
+	return KeyMap{bindings: bindings}
+}
+
+// IsAction reports whether the name is an action that can be bound.
+func IsAction(name string) bool {
+	for _, def := range actionDefaults {
+		if string(def.action) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Binding returns the binding of the action.
+func (k KeyMap) Binding(action Action) key.Binding {
+	return k.bindings[action]
+}
+
+// Matches reports whether the key is bound to the action.
+func (k KeyMap) Matches(msg tea.KeyMsg, action Action) bool {
+	return key.Matches(msg, k.bindings[action])
+}
+
+// Action returns the first of the actions the key is bound to.
+func (k KeyMap) Action(msg tea.KeyMsg, actions []Action) (Action, bool) {
+	for _, action := range actions {
+		if k.Matches(msg, action) {
+			return action, true
+		}
+	}
+	return "", false
+}
+
+// ShortHelp returns "key: Help" for each of the bound actions, in order.
+func (k KeyMap) ShortHelp(actions []Action) []string {
+	help := make([]string, 0, len(actions))
+	for _, action := range actions {
+		if text := k.HelpText(action, ""); text != "" {
+			help = append(help, text)
+		}
+	}
+	return help
+}
+
+// HelpText returns "key: desc" for the action, or "" if it is unbound. The
+// action's own help is used when desc is empty.
+func (k KeyMap) HelpText(action Action, desc string) string {
+	binding := k.bindings[action]
+	if !binding.Enabled() {
+		return ""
+	}
+	if desc == "" {
+		desc = binding.Help().Desc
+	}
+	return binding.Help().Key + ": " + desc
+}
+
+// JoinHelp joins help texts for a status line, skipping unbound ones.
+func JoinHelp(texts ...string) string {
+	parts := make([]string, 0, len(texts))
+	for _, text := range texts {
+		if text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, " | ")
+}
+
+// helpKeys shows the keys the way they are written in the status bar.
+func helpKeys(keys []string) string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k
+		if k == " " {
+			names[i] = "space"
+		}
+	}
+	return strings.Join(names, "/")
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/robertcurry0216/cross/internal/keymap"
+)
+
+func ClueScreenUpdate(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		action, _ := m.state.Keys.Action(msg, keymap.ClueActions)
+		switch action {
+		case keymap.NextUnsolved:
+			SelectNextUnsolvedClue(&m, true)
+		case keymap.PrevUnsolved:
+			SelectNextUnsolvedClue(&m, false)
+		case keymap.Up:
+			SelectNextClue(&m, false)
+		case keymap.Down:
+			SelectNextClue(&m, true)
+		case keymap.Left:
+			SelectNextInWord(&m, false)
+		case keymap.Right:
+			SelectNextInWord(&m, true)
+		case keymap.Delete:
+			if cell, ok := GetSelectedCell(&m); ok {
+				if cell.IsEmpty() {
+					SelectNextInWord(&m, false)
+					if cell, ok = GetSelectedCell(&m); !ok {
+						return m, nil
+					}
+				}
+				cell.Clear()
+				m.AutoSave()
+			}
+		case keymap.ByClue:
+			// back to the grid
+			m.PopView()
+		default:
+			if m.state.Keys.Matches(msg, keymap.Note) {
+				// notes are edited from the grid
+				return m, nil
+			}
+
+			if msg.Type == tea.KeyRunes && len(msg.Runes) == 1 && !msg.Alt && m.state.Puzzle.AllowsInput(msg.Runes[0]) {
+				SetLetter(&m, string(msg.Runes))
+				SelectNextInWord(&m, true)
+				m.AutoSave()
+				return m, nil
+			}
+
+			// checks, reveals and the other screens work as they do in the grid
+			return PuzzleKeyUpdate(m, msg)
+		}
+	}
+	return m, nil
+}
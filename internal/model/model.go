@@ -3,7 +3,10 @@ package model
 import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/robertcurry0216/cross/common"
+	"github.com/robertcurry0216/cross/internal/config"
+	"github.com/robertcurry0216/cross/internal/keymap"
 	"github.com/robertcurry0216/cross/internal/screen"
+	"github.com/robertcurry0216/cross/internal/theme"
 )
 
 type Model struct {
@@ -12,9 +15,10 @@ type Model struct {
 
 // Constructor
 
-func NewModel() Model {
+func NewModel(cfg config.Config, th *theme.Theme) Model {
 	views := make([]common.Viewable, 0, 10)
-	return Model{state: common.State{Views: views}}
+	keys := keymap.New(cfg.Keys.Overrides())
+	return Model{state: common.State{Views: views, Config: cfg, Keys: keys, Theme: th}}
 }
 
 // bubble tea functions
@@ -36,18 +40,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// global actions
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "esc":
-			m.PopView()
-			if len(m.state.Views) == 0 {
+		if m.state.Keys.Matches(msg, keymap.Quit) {
+			if m.state.Puzzle != nil {
 				m.state.Puzzle.Save()
-				return m, tea.Quit
-			} else {
-				return m, nil
 			}
-		case "ctrl+c":
 			return m, tea.Quit
 		}
+		// let an open text field have the key, to close itself with esc
+		if m.state.InputCaptured() {
+			break
+		}
+		if m.state.Keys.Matches(msg, keymap.Back) {
+			return m, m.GoBack()
+		}
 	case tea.WindowSizeMsg:
 		m.state.Width = msg.Width
 		m.state.Height = msg.Height
@@ -62,6 +67,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch m.state.Views[len(m.state.Views)-1].(type) {
 	case *screen.PuzzleScreen:
 		return PuzzleScreenUpdate(m, msg)
+	case *screen.MetaScreen:
+		return MetaScreenUpdate(m, msg)
+	case *screen.ClueScreen:
+		return ClueScreenUpdate(m, msg)
+	case *screen.LibraryScreen:
+		return LibraryScreenUpdate(m, msg)
+	case *screen.PaletteScreen:
+		return PaletteScreenUpdate(m, msg)
 	}
 
 	// catch all return
@@ -70,11 +83,40 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // methods
 
+// State returns the state the screens are drawn from.
+func (m Model) State() common.State {
+	return m.state
+}
+
 func (m *Model) PushView(view common.Viewable) {
 	view.Init(m.state)
 	m.state.Views = append(m.state.Views, view)
 }
 
+// GoBack closes the top view. Closing the last one saves the puzzle and
+// quits, and going back to the library closes the puzzle.
+func (m *Model) GoBack() tea.Cmd {
+	m.PopView()
+	if len(m.state.Views) == 0 {
+		if m.state.Puzzle != nil {
+			m.state.Puzzle.Save()
+		}
+		return tea.Quit
+	}
+	if library, ok := m.state.Views[len(m.state.Views)-1].(*screen.LibraryScreen); ok && m.state.Puzzle != nil {
+		ClosePuzzle(m, library)
+	}
+	return nil
+}
+
+// AutoSave saves the puzzle after a change, unless autosave is turned off
+// and it is left until exit.
+func (m *Model) AutoSave() {
+	if m.state.Config.General.Autosave {
+		m.state.Puzzle.Save()
+	}
+}
+
 func (m *Model) PopView() {
 	if len(m.state.Views) > 0 {
 		m.state.Views = m.state.Views[:len(m.state.Views)-1]
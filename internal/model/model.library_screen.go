@@ -0,0 +1,113 @@
+package model
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/robertcurry0216/cross/internal/keymap"
+	"github.com/robertcurry0216/cross/internal/puzzle"
+	"github.com/robertcurry0216/cross/internal/screen"
+)
+
+// OpenLibrary shows the puzzles found in the directories.
+func OpenLibrary(m *Model, dirs []string) {
+	m.PushView(&screen.LibraryScreen{Dirs: dirs})
+}
+
+func LibraryScreenUpdate(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	library, ok := m.state.Views[len(m.state.Views)-1].(*screen.LibraryScreen)
+	if !ok {
+		return m, nil
+	}
+	if m.state.LibraryView.Filtering {
+		return LibraryFilterUpdate(m, msg)
+	}
+
+	view := &m.state.LibraryView
+	entries := library.Visible(*view)
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		action, _ := m.state.Keys.Action(msg, keymap.LibraryActions)
+		switch action {
+		case keymap.Up:
+			view.Selected = max(view.Selected-1, 0)
+		case keymap.Down:
+			view.Selected = max(min(view.Selected+1, len(entries)-1), 0)
+		case keymap.Sort:
+			view.Sort = view.Sort.Next()
+			view.Selected = 0
+		case keymap.Filter:
+			view.Filtering = true
+		case keymap.Refresh:
+			var path string
+			if view.Selected < len(entries) {
+				path = entries[view.Selected].Path
+			}
+			library.Refresh()
+			library.Error = ""
+			selectEntry(&m, library, path)
+		case keymap.Open:
+			if view.Selected >= len(entries) {
+				return m, nil
+			}
+			p, err := puzzle.LoadFromFile(entries[view.Selected].Path)
+			if err != nil {
+				library.Error = fmt.Sprintf("Error loading puzzle: %v", err)
+				return m, nil
+			}
+			library.Error = ""
+			OpenPuzzle(&m, p)
+		case keymap.Help:
+			m.PushView(&screen.HelpScreen{})
+		}
+	}
+	return m, nil
+}
+
+func LibraryFilterUpdate(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	view := &m.state.LibraryView
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyEnter:
+			view.Filtering = false
+		case tea.KeyEsc:
+			view.Filtering = false
+			view.Filter = ""
+		case tea.KeyBackspace:
+			if runes := []rune(view.Filter); len(runes) > 0 {
+				view.Filter = string(runes[:len(runes)-1])
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			view.Filter += string(msg.Runes)
+		}
+		view.Selected = 0
+	}
+	return m, nil
+}
+
+// ClosePuzzle saves the open puzzle on the way back to the library, and
+// reads it again so its entry shows the new progress.
+func ClosePuzzle(m *Model, library *screen.LibraryScreen) {
+	puz := m.state.Puzzle
+	m.state.Puzzle = nil
+	puz.Save()
+	if puz.Builder == nil {
+		return
+	}
+	path := puz.Builder.Path()
+	library.RefreshEntry(path)
+	selectEntry(m, library, path)
+}
+
+// selectEntry selects the puzzle at path, which may have moved in the list,
+// or the first one if it is gone.
+func selectEntry(m *Model, library *screen.LibraryScreen, path string) {
+	view := &m.state.LibraryView
+	view.Selected = 0
+	for i, entry := range library.Visible(*view) {
+		if entry.Path == path {
+			view.Selected = i
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/robertcurry0216/cross/internal/screen"
+)
+
+func MetaScreenUpdate(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	view, ok := m.state.Views[len(m.state.Views)-1].(*screen.MetaScreen)
+	if !ok {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.PopView()
+			return m, nil
+		case tea.KeyBackspace:
+			if runes := []rune(view.Answer); len(runes) > 0 {
+				view.Answer = string(runes[:len(runes)-1])
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			for _, r := range msg.Runes {
+				if unicode.IsPrint(r) {
+					view.Answer += string(unicode.ToUpper(r))
+				}
+			}
+		default:
+			return m, nil
+		}
+
+		m.state.Puzzle.Progress.MetaAnswer = view.Answer
+		m.AutoSave()
+	}
+	return m, nil
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/robertcurry0216/cross/internal/keymap"
+	"github.com/robertcurry0216/cross/internal/screen"
+)
+
+func PaletteScreenUpdate(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	palette, ok := m.state.Views[len(m.state.Views)-1].(*screen.PaletteScreen)
+	if !ok {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		items := palette.Items(m.state)
+		// text goes to the query, so only other keys can be bound
+		var action keymap.Action
+		if msg.Type != tea.KeyRunes && msg.Type != tea.KeySpace {
+			action, _ = m.state.Keys.Action(msg, keymap.PaletteKeyActions)
+		}
+		switch {
+		case action == keymap.Back:
+			m.PopView()
+		case action == keymap.Up || msg.Type == tea.KeyShiftTab:
+			palette.Selected = max(palette.Selected-1, 0)
+		case action == keymap.Down || msg.Type == tea.KeyTab:
+			palette.Selected = max(min(palette.Selected+1, len(items)-1), 0)
+		case action == keymap.Open:
+			if palette.Selected >= len(items) {
+				return m, nil
+			}
+			item := items[palette.Selected]
+			m.PopView()
+			if item.Clue != nil {
+				SelectClue(&m, item.Clue)
+				return m, nil
+			}
+			return PuzzleActionUpdate(m, item.Action)
+		case msg.Type == tea.KeyBackspace:
+			if runes := []rune(palette.Query); len(runes) > 0 {
+				palette.Query = string(runes[:len(runes)-1])
+			}
+			palette.Selected = 0
+		case msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace:
+			palette.Query += string(msg.Runes)
+			palette.Selected = 0
+		}
+	}
+	return m, nil
+}
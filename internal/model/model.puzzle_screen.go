@@ -1,132 +1,293 @@
 package model
 
 import (
-	"regexp"
+	"fmt"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/robertcurry0216/cross/common"
+	"github.com/robertcurry0216/cross/internal/config"
+	"github.com/robertcurry0216/cross/internal/keymap"
 	"github.com/robertcurry0216/cross/internal/puzzle"
 	puz "github.com/robertcurry0216/cross/internal/puzzle"
+	"github.com/robertcurry0216/cross/internal/screen"
 )
 
 func PuzzleScreenUpdate(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.state.PuzzleView.EditingNote {
+		return NoteEditorUpdate(m, msg)
+	}
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		return PuzzleMouseUpdate(m, msg)
+	case tea.KeyMsg:
+		// moving with the keys brings the selected clue back into view
+		m.state.PuzzleView.ClueScroll = 0
+	}
+	if m.state.Config.General.Input == config.InputVim {
+		return VimUpdate(m, msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		m.state.Debug = msg.String()
-		switch msg.String() {
-		case "up":
-			if m.state.PuzzleView.Layout == common.LayoutPuzzleFocus {
+		return PuzzleKeyUpdate(m, msg)
+	}
+	return m, nil
+}
+
+// PuzzleKeyUpdate runs the action the key is bound to, or types the letter.
+func PuzzleKeyUpdate(m Model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.state.Debug = msg.String()
+	m.state.PuzzleView.Message = ""
+	if action, ok := m.state.Keys.Action(msg, keymap.PuzzleActions); ok {
+		return PuzzleActionUpdate(m, action)
+	}
+
+	if msg.Type == tea.KeyRunes && len(msg.Runes) == 1 && !msg.Alt && m.state.Puzzle.AllowsInput(msg.Runes[0]) {
+		SetLetter(&m, string(msg.Runes))
+		SelectAfterInput(&m)
+		m.AutoSave()
+	}
+	return m, nil
+}
+
+// PuzzleActionUpdate runs one of the grid's actions.
+func PuzzleActionUpdate(m Model, action keymap.Action) (tea.Model, tea.Cmd) {
+	switch action {
+	case keymap.Up:
+		if m.state.PuzzleView.Layout == common.LayoutPuzzleFocus {
+			if !TurnTo(&m, true) {
 				SelectNextCell(&m, -1, 0)
-			} else {
-				SelectNextClue(&m, false)
 			}
-			return m, nil
-		case "down":
-			if m.state.PuzzleView.Layout == common.LayoutPuzzleFocus {
+		} else {
+			SelectNextClue(&m, false)
+		}
+		return m, nil
+	case keymap.Down:
+		if m.state.PuzzleView.Layout == common.LayoutPuzzleFocus {
+			if !TurnTo(&m, true) {
 				SelectNextCell(&m, 1, 0)
-			} else {
-				SelectNextClue(&m, true)
 			}
-			return m, nil
-		case "left":
-			if m.state.PuzzleView.Layout == common.LayoutPuzzleFocus || !m.state.PuzzleView.IsVert {
+		} else {
+			SelectNextClue(&m, true)
+		}
+		return m, nil
+	case keymap.Left:
+		if m.state.PuzzleView.Layout == common.LayoutPuzzleFocus {
+			if !TurnTo(&m, false) {
 				SelectNextCell(&m, 0, -1)
-			} else {
-				SelectNextCell(&m, -1, 0)
 			}
-			return m, nil
-		case "right":
-			if m.state.PuzzleView.Layout == common.LayoutPuzzleFocus || !m.state.PuzzleView.IsVert {
+		} else if !m.state.PuzzleView.IsVert {
+			SelectNextCell(&m, 0, -1)
+		} else {
+			SelectNextCell(&m, -1, 0)
+		}
+		return m, nil
+	case keymap.Right:
+		if m.state.PuzzleView.Layout == common.LayoutPuzzleFocus {
+			if !TurnTo(&m, false) {
 				SelectNextCell(&m, 0, 1)
-			} else {
-				SelectNextCell(&m, 1, 0)
 			}
-			return m, nil
-		case " ":
-			m.state.PuzzleView.IsVert = !m.state.PuzzleView.IsVert
-			return m, nil
-		case "backspace":
-			if cell, ok := GetSelectedCell(&m); ok {
-				if cell.IsEmpty() {
-					if m.state.PuzzleView.IsVert {
-						SelectNextCell(&m, -1, 0)
-					} else {
-						SelectNextCell(&m, 0, -1)
-					}
-					if cell, ok = GetSelectedCell(&m); !ok {
-						return m, nil
-					}
+		} else if !m.state.PuzzleView.IsVert {
+			SelectNextCell(&m, 0, 1)
+		} else {
+			SelectNextCell(&m, 1, 0)
+		}
+		return m, nil
+	case keymap.ToggleDirection:
+		m.state.PuzzleView.IsVert = !m.state.PuzzleView.IsVert
+		return m, nil
+	case keymap.NextClue, keymap.PrevClue:
+		if m.state.Config.Typing.NextClueUnfilled {
+			SelectNextUnsolvedClue(&m, action == keymap.NextClue)
+		} else {
+			SelectNextClue(&m, action == keymap.NextClue)
+		}
+		return m, nil
+	case keymap.NextUnsolved, keymap.PrevUnsolved:
+		SelectNextUnsolvedClue(&m, action == keymap.NextUnsolved)
+		return m, nil
+	case keymap.FilterClues:
+		view := &m.state.PuzzleView
+		view.UnfilledOnly = !view.UnfilledOnly
+		view.ClueScroll = 0
+		view.Message = "Showing all clues"
+		if view.UnfilledOnly {
+			view.Message = "Showing unfilled clues"
+		}
+	case keymap.Delete:
+		if cell, ok := GetSelectedCell(&m); ok {
+			if cell.IsEmpty() {
+				SelectNextInput(&m, false)
+				if cell, ok = GetSelectedCell(&m); !ok {
+					return m, nil
 				}
-				*cell.Input = '-'
 			}
-		case "ctrl+l":
-			// check letter
-			if cell, ok := GetSelectedCell(&m); ok {
+			cell.Clear()
+			m.AutoSave()
+		}
+	case keymap.CheckLetter:
+		if cell, ok := GetSelectedCell(&m); ok {
+			cell.ShowChecked = true
+		}
+	case keymap.CheckWord:
+		if clue := GetSelectedClue(&m); clue != nil {
+			for _, c := range clue.AllCells() {
+				c.ShowChecked = true
+			}
+		}
+	case keymap.CheckPuzzle:
+		for _, cell := range m.state.Puzzle.Grid {
+			if !cell.IsBlank() {
 				cell.ShowChecked = true
 			}
-		case "ctrl+w":
-			// check word
-			if cell, ok := GetSelectedCell(&m); ok {
-				var clue *puzzle.Clue
-				if m.state.PuzzleView.IsVert {
-					clue = cell.ClueVert
-				} else {
-					clue = cell.ClueHoriz
-				}
-
-				for _, c := range clue.Cells {
-					c.ShowChecked = true
-				}
+		}
+	case keymap.RevealWord:
+		if clue := GetSelectedClue(&m); clue != nil {
+			for _, c := range clue.AllCells() {
+				c.SetInput(c.Solution)
 			}
-		case "ctrl+a":
-			// check puzzle
-			for _, cell := range m.state.Puzzle.Grid {
-				if !cell.IsBlank() {
-					cell.ShowChecked = true
-				}
+		}
+		m.AutoSave()
+	case keymap.RevealPuzzle:
+		for _, cell := range m.state.Puzzle.Grid {
+			cell.SetInput(cell.Solution)
+		}
+		m.AutoSave()
+	case keymap.Note:
+		// annotate the selected clue
+		if clue := GetSelectedClue(&m); clue != nil {
+			m.state.PuzzleView.EditingNote = true
+			m.state.PuzzleView.NoteDraft = clue.Root().Note
+		}
+	case keymap.Save:
+		m.state.Puzzle.Save()
+		m.state.PuzzleView.Message = "Saved"
+	case keymap.Info:
+		// puzzle info and notes
+		m.PushView(&screen.InfoScreen{})
+	case keymap.ByClue:
+		// solve one clue at a time
+		m.PushView(&screen.ClueScreen{})
+	case keymap.Meta:
+		// circled letters and meta answer
+		m.PushView(&screen.MetaScreen{})
+	case keymap.Help:
+		m.PushView(&screen.HelpScreen{})
+	case keymap.ToggleLayout:
+		// toggle focus
+		if m.state.PuzzleView.Layout == common.LayoutPuzzleFocus {
+			m.state.PuzzleView.Layout = common.LayoutClueFocus
+		} else {
+			m.state.PuzzleView.Layout = common.LayoutPuzzleFocus
+		}
+	case keymap.ClearWord:
+		if clue := GetSelectedClue(&m); clue != nil {
+			for _, c := range clue.AllCells() {
+				c.Clear()
 			}
-		case "ctrl+r":
-			// reveal word
-			if cell, ok := GetSelectedCell(&m); ok {
-				var clue *puzzle.Clue
-				if m.state.PuzzleView.IsVert {
-					clue = cell.ClueVert
-				} else {
-					clue = cell.ClueHoriz
-				}
+		}
+		m.AutoSave()
+	case keymap.ClearPuzzle:
+		for _, cell := range m.state.Puzzle.Grid {
+			cell.Clear()
+			cell.ShowChecked = false
+		}
+		m.AutoSave()
+	case keymap.Export:
+		if path, err := puzzle.ExportText(m.state.Puzzle); err != nil {
+			m.state.PuzzleView.Message = fmt.Sprintf("Export failed: %v", err)
+		} else {
+			m.state.PuzzleView.Message = "Exported to " + path
+		}
+	case keymap.Palette:
+		m.PushView(&screen.PaletteScreen{})
+	}
+	return m, nil
+}
 
-				for _, c := range clue.Cells {
-					*c.Input = c.Solution
-				}
+// PuzzleMouseUpdate selects the clicked cell or clue, and scrolls the clue
+// panel with the wheel.
+func PuzzleMouseUpdate(m Model, msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	s, ok := m.state.Views[len(m.state.Views)-1].(*screen.PuzzleScreen)
+	if !ok {
+		return m, nil
+	}
+	view := &m.state.PuzzleView
+	hit := s.HitTest(m.state, msg.X, msg.Y)
+
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp && hit.InClues:
+		view.ClueScroll = hit.ClueScroll - 3
+	case msg.Button == tea.MouseButtonWheelDown && hit.InClues:
+		view.ClueScroll = hit.ClueScroll + 3
+	case msg.Button != tea.MouseButtonLeft || msg.Action != tea.MouseActionPress:
+		return m, nil
+	case hit.Cell != nil:
+		isVert := view.IsVert
+		if hit.X == view.X && hit.Y == view.Y {
+			// a second click swaps direction
+			isVert = !isVert
+		}
+		if isVert && hit.Cell.ClueVert == nil {
+			isVert = false
+		} else if !isVert && hit.Cell.ClueHoriz == nil {
+			isVert = true
+		}
+		SelectCell(&m, hit.Cell, isVert)
+		view.Message = ""
+	case hit.Clue != nil:
+		SelectClue(&m, hit.Clue)
+		view.ClueScroll = 0
+		view.Message = ""
+	}
+	return m, nil
+}
+
+// NoteEditorUpdate edits the note of the selected clue.
+func NoteEditorUpdate(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	view := &m.state.PuzzleView
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyEnter:
+			if clue := GetSelectedClue(&m); clue != nil {
+				clue.Root().Note = strings.TrimSpace(view.NoteDraft)
+				m.AutoSave()
 			}
-		case "tab":
-			// toggle focus
-			if m.state.PuzzleView.Layout == common.LayoutPuzzleFocus {
-				m.state.PuzzleView.Layout = common.LayoutClueFocus
-			} else {
-				m.state.PuzzleView.Layout = common.LayoutPuzzleFocus
-			}
-		default:
-			pattern := `^[a-zA-Z]$`
-			re := regexp.MustCompile(pattern)
-			if re.MatchString(msg.String()) {
-				SetLetter(&m, msg.String())
-				if m.state.PuzzleView.IsVert {
-					SelectNextCell(&m, 1, 0)
-				} else {
-					SelectNextCell(&m, 0, 1)
-				}
+			view.EditingNote = false
+		case tea.KeyEsc:
+			view.EditingNote = false
+		case tea.KeyBackspace:
+			if runes := []rune(view.NoteDraft); len(runes) > 0 {
+				view.NoteDraft = string(runes[:len(runes)-1])
 			}
-			m.state.Puzzle.Save()
-			return m, nil
+		case tea.KeyRunes, tea.KeySpace:
+			view.NoteDraft += string(msg.Runes)
 		}
 	}
 	return m, nil
 }
 
+// OpenPuzzle shows the puzzle screen for the puzzle. The info screen is shown
+// on top the first time a puzzle with notes is opened, as the notes often
+// explain how to solve it.
+func OpenPuzzle(m *Model, puzzle *puz.Puzzle) {
+	SetPuzzle(m, puzzle)
+	m.PushView(&screen.PuzzleScreen{})
+
+	if puzzle.Notes != "" && !puzzle.Progress.NotesSeen {
+		m.PushView(&screen.InfoScreen{})
+		puzzle.Progress.NotesSeen = true
+		if err := puzzle.SaveProgress(); err != nil {
+			m.state.PuzzleView.Message = fmt.Sprintf("Saving progress failed: %v", err)
+		}
+	}
+}
+
 func SetPuzzle(m *Model, puzzle *puz.Puzzle) {
 	m.state.Puzzle = puzzle
+	m.state.PuzzleView = common.PuzzleView{Layout: m.state.PuzzleView.Layout}
 	// lazy way to ensure the initial cell isn't blank
 	SelectNextCell(m, 0, 1)
 	SelectNextCell(m, 0, -1)
@@ -163,9 +324,145 @@ func SelectNextCell(m *Model, yDir, xDir int) {
 	}
 }
 
+// SelectNextInput moves one cell along the current answer, continuing into
+// the next (or previous) part of a multi-part answer at the end of an entry.
+func SelectNextInput(m *Model, forward bool) {
+	step := 1
+	if !forward {
+		step = -1
+	}
+
+	if cell, ok := GetSelectedCell(m); ok {
+		if clue := GetSelectedClue(m); clue != nil {
+			var part *puzzle.Clue
+			if forward && clue.LastCell() == cell {
+				part = clue.NextPart()
+			} else if !forward && clue.FirstCell() == cell {
+				part = clue.PrevPart()
+			}
+
+			if part != nil {
+				target := part.FirstCell()
+				if !forward {
+					target = part.LastCell()
+				}
+				SelectCell(m, target, part.IsVert)
+				return
+			}
+		}
+	}
+
+	if m.state.PuzzleView.IsVert {
+		SelectNextCell(m, step, 0)
+	} else {
+		SelectNextCell(m, 0, step)
+	}
+}
+
+// SelectAfterInput moves on from a typed letter, following the typing
+// settings: past filled cells, and from the end of the answer to the next
+// unfinished one.
+func SelectAfterInput(m *Model) {
+	typing := m.state.Config.Typing
+	cell, ok := GetSelectedCell(m)
+	clue := GetSelectedClue(m)
+	if !ok || clue == nil {
+		SelectNextInput(m, true)
+		return
+	}
+
+	// the answer's cells, through every part, and the part each is in
+	var cells []*puzzle.Cell
+	var parts []*puzzle.Clue
+	current := -1
+	for _, part := range clue.Root().AllParts() {
+		for _, c := range part.Cells {
+			if c == cell {
+				current = len(cells)
+			}
+			cells = append(cells, c)
+			parts = append(parts, part)
+		}
+	}
+
+	for i := current + 1; i < len(cells); i++ {
+		if !typing.SkipFilled || cells[i].IsEmpty() {
+			SelectCell(m, cells[i], parts[i].IsVert)
+			return
+		}
+	}
+
+	// back to a gap earlier in the answer
+	if typing.SkipFilled {
+		for i := 0; i < current; i++ {
+			if cells[i].IsEmpty() {
+				SelectCell(m, cells[i], parts[i].IsVert)
+				return
+			}
+		}
+	}
+
+	if typing.JumpToNextClue {
+		SelectNextUnsolvedClue(m, true)
+		return
+	}
+	SelectNextInput(m, true)
+}
+
+// TurnTo changes to the direction of an arrow pressed across the current
+// one, when the typing settings ask for it. It reports whether it turned.
+func TurnTo(m *Model, isVert bool) bool {
+	view := &m.state.PuzzleView
+	cell, ok := GetSelectedCell(m)
+	if !ok || !m.state.Config.Typing.ArrowChangesDirection || view.IsVert == isVert {
+		return false
+	}
+	if (isVert && cell.ClueVert == nil) || (!isVert && cell.ClueHoriz == nil) {
+		return false
+	}
+	view.IsVert = isVert
+	return true
+}
+
+// SelectNextInWord moves one cell along the current answer but never past
+// its first or last cell.
+func SelectNextInWord(m *Model, forward bool) {
+	cell, ok := GetSelectedCell(m)
+	clue := GetSelectedClue(m)
+	if !ok || clue == nil {
+		return
+	}
+
+	cells := clue.AllCells()
+	if (forward && cells[len(cells)-1] == cell) || (!forward && cells[0] == cell) {
+		return
+	}
+	SelectNextInput(m, forward)
+}
+
+// SelectCell moves the cursor to the given cell and sets the direction.
+func SelectCell(m *Model, cell *puzzle.Cell, isVert bool) {
+	view := &m.state.PuzzleView
+	x, y, ok := m.state.Puzzle.PositionOf(cell)
+	if !ok {
+		return
+	}
+
+	if cur, ok := GetSelectedCell(m); ok {
+		cur.IsSelected = false
+	}
+	view.X = x
+	view.Y = y
+	view.IsVert = isVert
+	cell.IsSelected = true
+	if clue := GetSelectedClue(m); clue != nil {
+		clue.Selected = true
+	}
+}
+
 func SetLetter(m *Model, letter string) {
-	if cell, ok := GetSelectedCell(m); ok && len(letter) == 1 {
-		*cell.Input = strings.ToUpper(letter)[0]
+	if cell, ok := GetSelectedCell(m); ok && letter != "" {
+		cell.SetInput(letter)
 		cell.ShowChecked = false
 	}
 }
@@ -175,31 +472,96 @@ func GetSelectedCell(m *Model) (*puzzle.Cell, bool) {
 	return cell, cell != nil
 }
 
-func SelectNextClue(m *Model, forward bool) {
-	view := &m.state.PuzzleView
-	puz := m.state.Puzzle
-
-	// Get the current clue
+// GetSelectedClue returns the clue under the cursor in the current direction.
+func GetSelectedClue(m *Model) *puzzle.Clue {
 	cell, ok := GetSelectedCell(m)
 	if !ok {
+		return nil
+	}
+	if m.state.PuzzleView.IsVert {
+		return cell.ClueVert
+	}
+	return cell.ClueHoriz
+}
+
+// clueOrder lists the answers in the order they are moved through: the
+// direction of clue, then the other direction when wrapping between them.
+// Continuations of multi-part answers are left out.
+func clueOrder(m *Model, isVert bool) []*puzzle.Clue {
+	puz := m.state.Puzzle
+	var lists [][]*puzzle.Clue
+	switch {
+	case m.state.Config.Typing.WrapDirections:
+		lists = [][]*puzzle.Clue{puz.AcrossClues, puz.DownClues}
+	case isVert:
+		lists = [][]*puzzle.Clue{puz.DownClues}
+	default:
+		lists = [][]*puzzle.Clue{puz.AcrossClues}
+	}
+
+	clues := make([]*puzzle.Clue, 0, len(puz.Clues))
+	for _, list := range lists {
+		for _, clue := range list {
+			if !clue.IsContinuation() {
+				clues = append(clues, clue)
+			}
+		}
+	}
+	return clues
+}
+
+// SelectNextUnsolvedClue moves to the first empty cell of the next clue, in
+// clueOrder, that still has empty cells.
+func SelectNextUnsolvedClue(m *Model, forward bool) {
+	current := GetSelectedClue(m)
+	isVert := m.state.PuzzleView.IsVert
+	if current != nil {
+		isVert = current.Root().IsVert
+	}
+	clues := clueOrder(m, isVert)
+	if len(clues) == 0 {
 		return
 	}
-	cell.IsSelected = false
-	var currentClue *puzzle.Clue
-	var clues []*puzzle.Clue
 
-	if view.IsVert {
-		currentClue = cell.ClueVert
-		clues = puz.DownClues
-	} else {
-		currentClue = cell.ClueHoriz
-		clues = puz.AcrossClues
+	currentIndex := 0
+	if current != nil {
+		for i, clue := range clues {
+			if clue == current.Root() {
+				currentIndex = i
+				break
+			}
+		}
 	}
 
-	if currentClue == nil || len(clues) == 0 {
+	nextIndex := currentIndex
+	for range clues {
+		if forward {
+			nextIndex = (nextIndex + 1) % len(clues)
+		} else {
+			nextIndex = (nextIndex - 1 + len(clues)) % len(clues)
+		}
+		for _, part := range clues[nextIndex].AllParts() {
+			for _, cell := range part.Cells {
+				if cell.IsEmpty() {
+					SelectCell(m, cell, part.IsVert)
+					return
+				}
+			}
+		}
+	}
+}
+
+// SelectNextClue moves to the start of the next (or previous) clue.
+func SelectNextClue(m *Model, forward bool) {
+	currentClue := GetSelectedClue(m)
+	if currentClue == nil {
 		return
 	}
 
+	// multi-part answers are listed under the direction of their first part
+	currentClue = currentClue.Root()
+	clues := clueOrder(m, currentClue.IsVert)
+
 	// Find the index of the current clue
 	currentIndex := -1
 	for i, clue := range clues {
@@ -213,28 +575,14 @@ func SelectNextClue(m *Model, forward bool) {
 		return
 	}
 
-	// Calculate the next index
-	nextIndex := currentIndex
-	if forward {
-		nextIndex = (currentIndex + 1) % len(clues)
-	} else {
+	nextIndex := (currentIndex + 1) % len(clues)
+	if !forward {
 		nextIndex = (currentIndex - 1 + len(clues)) % len(clues)
 	}
 
 	// Select the first cell of the next clue
 	nextClue := clues[nextIndex]
 	if firstCell := nextClue.FirstCell(); firstCell != nil {
-		// Update the position
-		for y := 0; y < puz.Height; y++ {
-			for x := 0; x < puz.Width; x++ {
-				if puz.CellAt(x, y) == firstCell {
-					view.X = x
-					view.Y = y
-					firstCell.IsSelected = true
-					nextClue.Selected = true
-					return
-				}
-			}
-		}
+		SelectCell(m, firstCell, nextClue.IsVert)
 	}
 }
@@ -0,0 +1,232 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/robertcurry0216/cross/common"
+	"github.com/robertcurry0216/cross/internal/keymap"
+	"github.com/robertcurry0216/cross/internal/puzzle"
+)
+
+// VimUpdate handles the grid's keys in the vim input mode. Letters are only
+// typed in insert mode; normal mode moves around and : runs commands.
+func VimUpdate(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	vim := &m.state.PuzzleView.Vim
+	switch vim.Mode {
+	case common.VimInsert:
+		if key.Type == tea.KeyEsc {
+			vim.Mode = common.VimNormal
+			return m, nil
+		}
+		return PuzzleKeyUpdate(m, key)
+	case common.VimCommand:
+		return VimCommandUpdate(m, key)
+	}
+	return VimNormalUpdate(m, key)
+}
+
+func VimNormalUpdate(m Model, key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	vim := &m.state.PuzzleView.Vim
+	m.state.PuzzleView.Message = ""
+
+	count := max(vim.Count, 1)
+	pending := vim.Pending
+	vim.Pending = ""
+
+	// counts, like the 3 in 3l
+	if key.Type == tea.KeyRunes && len(key.Runes) == 1 && pending == "" {
+		if r := key.Runes[0]; r >= '1' && r <= '9' || (r == '0' && vim.Count > 0) {
+			vim.Count = vim.Count*10 + int(r-'0')
+			return m, nil
+		}
+	}
+	hasCount := vim.Count > 0
+	vim.Count = 0
+
+	switch pending {
+	case "g":
+		if key.String() == "g" {
+			if hasCount {
+				SelectClueNumber(&m, count)
+			} else {
+				SelectEndClue(&m, true)
+			}
+		}
+		return m, nil
+	case "r":
+		if key.Type == tea.KeyRunes && len(key.Runes) == 1 && m.state.Puzzle.AllowsInput(key.Runes[0]) {
+			SetLetter(&m, string(key.Runes))
+			m.AutoSave()
+		}
+		return m, nil
+	}
+
+	switch key.String() {
+	case "h":
+		repeat(count, func() { SelectNextCell(&m, 0, -1) })
+	case "l":
+		repeat(count, func() { SelectNextCell(&m, 0, 1) })
+	case "k":
+		repeat(count, func() { SelectNextCell(&m, -1, 0) })
+	case "j":
+		repeat(count, func() { SelectNextCell(&m, 1, 0) })
+	case "w":
+		repeat(count, func() { SelectNextClue(&m, true) })
+	case "b":
+		repeat(count, func() { SelectNextClue(&m, false) })
+	case "0", "^":
+		if clue := GetSelectedClue(&m); clue != nil {
+			SelectCell(&m, clue.FirstCell(), clue.IsVert)
+		}
+	case "$":
+		if clue := GetSelectedClue(&m); clue != nil {
+			SelectCell(&m, clue.LastCell(), clue.IsVert)
+		}
+	case "g":
+		vim.Pending = "g"
+		if hasCount {
+			vim.Count = count
+		}
+	case "G":
+		if hasCount {
+			SelectClueNumber(&m, count)
+		} else {
+			SelectEndClue(&m, false)
+		}
+	case "i":
+		vim.Mode = common.VimInsert
+	case "a":
+		SelectNextInWord(&m, true)
+		vim.Mode = common.VimInsert
+	case "x":
+		for i := 0; i < count; i++ {
+			if cell, ok := GetSelectedCell(&m); ok {
+				cell.Clear()
+			}
+			if i < count-1 {
+				SelectNextInWord(&m, true)
+			}
+		}
+		m.AutoSave()
+	case "r":
+		vim.Pending = "r"
+	case ":":
+		vim.Mode = common.VimCommand
+		vim.Command = ""
+	case "esc":
+		// clears a count or pending key
+	default:
+		// checks, reveals, the other screens and so on, but not typing
+		if _, ok := m.state.Keys.Action(key, keymap.PuzzleActions); ok {
+			return PuzzleKeyUpdate(m, key)
+		}
+	}
+	return m, nil
+}
+
+func VimCommandUpdate(m Model, key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	vim := &m.state.PuzzleView.Vim
+	switch key.Type {
+	case tea.KeyEsc:
+		vim.Mode = common.VimNormal
+	case tea.KeyEnter:
+		vim.Mode = common.VimNormal
+		return m, RunVimCommand(&m, vim.Command)
+	case tea.KeyBackspace:
+		if runes := []rune(vim.Command); len(runes) > 0 {
+			vim.Command = string(runes[:len(runes)-1])
+		} else {
+			vim.Mode = common.VimNormal
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		vim.Command += string(key.Runes)
+	}
+	return m, nil
+}
+
+var vimClueRe = regexp.MustCompile(`^(\d+)\s*(a|across|d|down)?$`)
+
+// RunVimCommand runs a : command: a clue like 12a or 5down to jump to it, w
+// to save, q to go back and wq or x to do both.
+func RunVimCommand(m *Model, command string) tea.Cmd {
+	command = strings.ToLower(strings.TrimSpace(command))
+	switch command {
+	case "":
+	case "w":
+		m.state.Puzzle.Save()
+		m.state.PuzzleView.Message = "Saved"
+	case "q", "q!":
+		return m.GoBack()
+	case "wq", "x":
+		m.state.Puzzle.Save()
+		return m.GoBack()
+	default:
+		match := vimClueRe.FindStringSubmatch(command)
+		if match == nil {
+			m.state.PuzzleView.Message = fmt.Sprintf("Not a command: %s", command)
+			return nil
+		}
+
+		number, _ := strconv.Atoi(match[1])
+		isVert := m.state.PuzzleView.IsVert
+		if match[2] != "" {
+			isVert = strings.HasPrefix(match[2], "d")
+		}
+		clue := m.state.Puzzle.FindClue(number, isVert)
+		if clue == nil && match[2] == "" {
+			clue = m.state.Puzzle.FindClue(number, !isVert)
+		}
+		if clue == nil {
+			m.state.PuzzleView.Message = fmt.Sprintf("No clue %s", strings.ToUpper(command))
+			return nil
+		}
+		SelectClue(m, clue)
+	}
+	return nil
+}
+
+// SelectClue moves the cursor to the start of the clue.
+func SelectClue(m *Model, clue *puzzle.Clue) {
+	if cell := clue.FirstCell(); cell != nil {
+		SelectCell(m, cell, clue.IsVert)
+	}
+}
+
+// SelectClueNumber moves to the clue with the number in the current
+// direction, if there is one.
+func SelectClueNumber(m *Model, number int) {
+	if clue := m.state.Puzzle.FindClue(number, m.state.PuzzleView.IsVert); clue != nil {
+		SelectClue(m, clue)
+	}
+}
+
+// SelectEndClue moves to the first or last clue in the current direction.
+func SelectEndClue(m *Model, first bool) {
+	clues := m.state.Puzzle.AcrossClues
+	if m.state.PuzzleView.IsVert {
+		clues = m.state.Puzzle.DownClues
+	}
+	if len(clues) == 0 {
+		return
+	}
+	if first {
+		SelectClue(m, clues[0])
+	} else {
+		SelectClue(m, clues[len(clues)-1])
+	}
+}
+
+func repeat(n int, f func()) {
+	for i := 0; i < n; i++ {
+		f()
+	}
+}
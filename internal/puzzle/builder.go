@@ -10,6 +10,7 @@ type Buildable interface {
 	Build() (*Puzzle, error)
 	Validate() error
 	Write()
+	Path() string
 }
 
 func NewBuilderFromFile(path string) (Buildable, error) {
@@ -32,18 +33,37 @@ func InitPuzzle(puz *Puzzle) error {
 	puz.Grid = make([]*Cell, size)
 
 	for i := 0; i < size; i++ {
-		puz.Grid[i] = NewCell()
-		if puz.Solution[i] != '.' {
-			puz.Grid[i].Solution = puz.Solution[i]
+		cell := NewCell()
+		cell.Solution = decodeSolutionByte(puz.Solution[i])
+		cell.input = decodeInputByte(puz.Input[i])
+		cell.store = func(value string) {
+			puz.Input[i] = encodeGridValue(value)
 		}
-		puz.Grid[i].Input = &puz.Input[i]
+		puz.Grid[i] = cell
 	}
+	puz.Charset = gridCharset(puz)
 
 	AssignClues(puz)
+	LinkClues(puz)
 
 	return nil
 }
 
+// gridCharset returns the characters that can be entered in the puzzle: the
+// letters A to Z plus anything that appears in a solution.
+func gridCharset(puz *Puzzle) map[rune]bool {
+	charset := make(map[rune]bool)
+	for r := 'A'; r <= 'Z'; r++ {
+		charset[r] = true
+	}
+	for _, cell := range puz.Grid {
+		for _, r := range cell.Solution {
+			charset[toUpperLatin1(r)] = true
+		}
+	}
+	return charset
+}
+
 func NeedsAcrossClue(puz *Puzzle, row, col int) bool {
 	cell := puz.CellAt(col, row)
 	if cell == nil || cell.IsBlank() {
@@ -114,6 +134,7 @@ func AssignClues(puz *Puzzle) error {
 			if needsDown {
 				clue := puz.Clues[clueIdx]
 				clue.Number = clueNum
+				clue.IsVert = true
 				puz.DownClues = append(puz.DownClues, clue)
 				cell.ClueVert = clue
 				clueIdx++
@@ -1,6 +1,7 @@
 package puzzle
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"os"
@@ -10,6 +11,8 @@ type PuzBuilder struct {
 	raw      []byte
 	filepath string
 	Puzzle   *Puzzle
+	Version  string
+	Encoding TextEncoding
 
 	// write locations
 	puzzleInput []byte
@@ -22,15 +25,28 @@ func NewPuzBuilder(raw []byte, path string) *PuzBuilder {
 
 func (b *PuzBuilder) Build() (*Puzzle, error) {
 	puz := NewPuzzle()
+	if len(b.raw) < 0x34 {
+		return nil, fmt.Errorf("Malformed .puz file: missing header")
+	}
 
 	// extract basic data
 	// checksum := binary.LittleEndian.Uint16(b.raw[0:2])
 	puz.Width = int(b.raw[0x2c])
 	puz.Height = int(b.raw[0x2d])
 
+	// the version decides how strings are encoded
+	version := b.raw[0x18:0x1c]
+	if i := bytes.IndexByte(version, 0); i != -1 {
+		version = version[:i]
+	}
+	b.Version = string(version)
+	b.Encoding = EncodingForVersion(b.Version)
+	puz.Format = fmt.Sprintf("Across Lite .puz v%s (%v)", b.Version, b.Encoding)
+
 	// extract grid information
 	gridSize := puz.Width * puz.Height
 	stream := NewByteStream(b.raw)
+	stream.Encoding = b.Encoding
 	stream.ChompN(0x34)
 
 	puz.Solution = make([]byte, gridSize)
@@ -106,8 +122,6 @@ func (b *PuzBuilder) Build() (*Puzzle, error) {
 			case "GEXT":
 				applyGEXT(puz, data)
 				b.gext = data
-			default:
-				fmt.Println(string(section))
 			}
 		} else {
 			break
@@ -122,6 +136,11 @@ func (b *PuzBuilder) Build() (*Puzzle, error) {
 	return puz, nil
 }
 
+// Path returns the file the puzzle was read from.
+func (b *PuzBuilder) Path() string {
+	return b.filepath
+}
+
 func (b *PuzBuilder) Write() {
 	b.updateRaw()
 	os.WriteFile(b.filepath, b.raw, 0644)
@@ -136,11 +155,18 @@ func (b *PuzBuilder) updateRaw() {
 	binary.LittleEndian.PutUint16(b.raw[0:2], cksum)
 }
 
+// GEXT flags
+const (
+	gextCircled byte = 0x80
+)
+
 func applyGEXT(puz *Puzzle, data []byte) {
 	for i, cell := range puz.Grid {
-		datum := data[i]
-		if datum&0x80 != 0 {
-			cell.IsCircled = true
+		if i >= len(data) {
+			break
+		}
+		if data[i]&gextCircled != 0 {
+			cell.Style.Circled = true
 		}
 	}
 }
@@ -196,16 +222,16 @@ func (b *PuzBuilder) getCheckSum() uint16 {
 
 	for _, metaField := range []string{b.Puzzle.Title, b.Puzzle.Author, b.Puzzle.Copyright} {
 		if len(metaField) > 0 {
-			cksum = ChecksumRegion([]byte(metaField+"\x00"), cksum) // Include null terminator
+			cksum = ChecksumRegion(append(b.Encoding.Encode(metaField), 0), cksum) // Include null terminator
 		}
 	}
 
 	for _, clue := range b.Puzzle.Clues {
-		cksum = ChecksumRegion([]byte(clue.Text), cksum)
+		cksum = ChecksumRegion(b.Encoding.Encode(clue.Text), cksum)
 	}
 
 	if len(b.Puzzle.Notes) > 0 {
-		cksum = ChecksumRegion([]byte(b.Puzzle.Notes+"\x00"), cksum) // Include null terminator
+		cksum = ChecksumRegion(append(b.Encoding.Encode(b.Puzzle.Notes), 0), cksum) // Include null terminator
 	}
 
 	return cksum
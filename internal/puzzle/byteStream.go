@@ -5,9 +5,10 @@ import (
 )
 
 type ByteStream struct {
-	Raw     []byte
-	Pointer int
-	Size    int
+	Raw      []byte
+	Pointer  int
+	Size     int
+	Encoding TextEncoding
 }
 
 func NewByteStream(raw []byte) *ByteStream {
@@ -43,7 +44,7 @@ func (s *ByteStream) ReadString() (string, int) {
 		return "", -1
 	}
 
-	str := string(s.Raw[s.Pointer : s.Pointer+nullIndex])
+	str := s.Encoding.Decode(s.Raw[s.Pointer : s.Pointer+nullIndex])
 	offset := nullIndex + 1 // Move past the null terminator
 
 	s.IncPointer(offset)
@@ -1,13 +1,28 @@
 package puzzle
 
+import (
+	"strings"
+	"unicode"
+)
+
+// CellStyle describes how a cell is decorated, independently of its contents.
+type CellStyle struct {
+	Circled bool
+	Shaded  bool
+	Color   string // background color, as a hex code or ANSI number
+	Mark    string // small label drawn in the top-left corner
+}
+
 type Cell struct {
 	ClueVert    *Clue
 	ClueHoriz   *Clue
-	Solution    byte
-	Input       *byte
+	Solution    string
 	IsSelected  bool
 	ShowChecked bool
-	IsCircled   bool
+	Style       CellStyle
+
+	input string
+	store func(string) // writes the input back to the puzzle file's grid
 }
 
 func NewCell() *Cell {
@@ -15,11 +30,33 @@ func NewCell() *Cell {
 }
 
 func (cell *Cell) IsBlank() bool {
-	return cell.Solution == 0
+	return cell.Solution == ""
+}
+
+func (cell *Cell) IsCircled() bool {
+	return cell.Style.Circled
 }
 
 func (cell *Cell) IsEmpty() bool {
-	return *cell.Input < 'A' || *cell.Input > 'Z'
+	return cell.input == ""
+}
+
+// Input returns the solver's entry for the cell, or "" when empty.
+func (cell *Cell) Input() string {
+	return cell.input
+}
+
+// SetInput sets the solver's entry for the cell, upper-casing it where the
+// grid can still hold it.
+func (cell *Cell) SetInput(value string) {
+	cell.input = strings.Map(toUpperLatin1, value)
+	if cell.store != nil {
+		cell.store(cell.input)
+	}
+}
+
+func (cell *Cell) Clear() {
+	cell.SetInput("")
 }
 
 func (cell *Cell) Number() int {
@@ -32,7 +69,7 @@ func (cell *Cell) Number() int {
 }
 
 func (cell *Cell) IsCorrect() bool {
-	return *cell.Input == cell.Solution
+	return !cell.IsEmpty() && strings.EqualFold(cell.input, cell.Solution)
 }
 
 // helpers
@@ -40,3 +77,41 @@ func (cell *Cell) IsCorrect() bool {
 func IsCellBlankOrNil(cell *Cell) bool {
 	return cell == nil || cell.IsBlank()
 }
+
+// decodeSolutionByte converts a .puz solution byte to a cell value. Grid
+// bytes are always ISO-8859-1, and '.' marks a blank square.
+func decodeSolutionByte(b byte) string {
+	if b == '.' || b == 0 {
+		return ""
+	}
+	return string(rune(b))
+}
+
+// decodeInputByte converts a .puz input byte to a cell value, where '-'
+// marks an empty square.
+func decodeInputByte(b byte) string {
+	if b == '-' {
+		return ""
+	}
+	return decodeSolutionByte(b)
+}
+
+// toUpperLatin1 upper-cases the rune, unless that takes a Latin-1 letter out
+// of Latin-1, as it does for ÿ and µ, which the grid bytes couldn't hold.
+func toUpperLatin1(r rune) rune {
+	if upper := unicode.ToUpper(r); upper <= 0xFF || r > 0xFF {
+		return upper
+	}
+	return r
+}
+
+// encodeGridValue converts a cell value back to a .puz grid byte.
+func encodeGridValue(value string) byte {
+	if value == "" {
+		return '-'
+	}
+	if encoded := EncodingLatin1.Encode(value); len(encoded) == 1 {
+		return encoded[0]
+	}
+	return '?'
+}
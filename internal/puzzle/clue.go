@@ -1,15 +1,28 @@
 package puzzle
 
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
 type Clue struct {
-	Text     string
-	Number   int
-	Selected bool
-	Cells    []*Cell
+	Text        string
+	Enumeration string // word lengths of the answer, e.g. "3,4" or "5-3"
+	Number      int
+	IsVert      bool
+	Selected    bool
+	Cells       []*Cell
+	Note        string // the solver's own annotation, kept in the progress file
+
+	// Parts lists every entry of a multi-part answer in answer order. The
+	// slice is shared by all of the parts and is nil for standalone clues.
+	Parts []*Clue
 }
 
 func NewClue(text string) *Clue {
 	cells := make([]*Cell, 0, 15)
-	return &Clue{Text: text, Cells: cells}
+	return &Clue{Text: text, Enumeration: parseEnumeration(text), Cells: cells}
 }
 
 func (clue *Clue) FirstCell() *Cell {
@@ -18,3 +31,306 @@ func (clue *Clue) FirstCell() *Cell {
 	}
 	return nil
 }
+
+func (clue *Clue) LastCell() *Cell {
+	if len(clue.Cells) > 0 {
+		return clue.Cells[len(clue.Cells)-1]
+	}
+	return nil
+}
+
+// Root returns the clue that heads the answer this clue belongs to.
+func (clue *Clue) Root() *Clue {
+	if clue == nil || len(clue.Parts) == 0 {
+		return clue
+	}
+	return clue.Parts[0]
+}
+
+// IsContinuation reports whether the clue only continues another clue's answer.
+func (clue *Clue) IsContinuation() bool {
+	return clue.Root() != clue
+}
+
+// SameAnswer reports whether both clues are parts of the same answer.
+func (clue *Clue) SameAnswer(other *Clue) bool {
+	return clue != nil && other != nil && clue.Root() == other.Root()
+}
+
+// AllParts returns the parts of the answer, or just the clue when standalone.
+func (clue *Clue) AllParts() []*Clue {
+	if len(clue.Parts) == 0 {
+		return []*Clue{clue}
+	}
+	return clue.Parts
+}
+
+// AllCells returns the cells of every part of the answer in answer order.
+func (clue *Clue) AllCells() []*Cell {
+	if len(clue.Parts) == 0 {
+		return clue.Cells
+	}
+	cells := make([]*Cell, 0, len(clue.Cells)*len(clue.Parts))
+	for _, part := range clue.Parts {
+		cells = append(cells, part.Cells...)
+	}
+	return cells
+}
+
+// IsFilled reports whether every cell of the answer has a letter.
+func (clue *Clue) IsFilled() bool {
+	for _, cell := range clue.AllCells() {
+		if cell.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// IsVerified reports whether the whole answer has been checked and is correct.
+func (clue *Clue) IsVerified() bool {
+	for _, cell := range clue.AllCells() {
+		if !cell.ShowChecked || !cell.IsCorrect() {
+			return false
+		}
+	}
+	return true
+}
+
+// Lengths returns the number of cells in each part of the answer.
+func (clue *Clue) Lengths() []int {
+	parts := clue.AllParts()
+	lengths := make([]int, len(parts))
+	for i, part := range parts {
+		lengths[i] = len(part.Cells)
+	}
+	return lengths
+}
+
+// NextPart returns the part following this one, or nil.
+func (clue *Clue) NextPart() *Clue {
+	for i, part := range clue.Parts {
+		if part == clue && i+1 < len(clue.Parts) {
+			return clue.Parts[i+1]
+		}
+	}
+	return nil
+}
+
+// PrevPart returns the part preceding this one, or nil.
+func (clue *Clue) PrevPart() *Clue {
+	for i, part := range clue.Parts {
+		if part == clue && i > 0 {
+			return clue.Parts[i-1]
+		}
+	}
+	return nil
+}
+
+// Key identifies the clue by number and direction, e.g. "17A".
+func (clue *Clue) Key() string {
+	return strconv.Itoa(clue.Number) + directionLetter(clue.IsVert)
+}
+
+// DisplayText returns the clue text without the part references that head
+// a multi-part answer and without a trailing enumeration, as both are shown
+// separately. Brackets that don't fit the answer, like a year, are left in.
+func (clue *Clue) DisplayText() string {
+	text := clue.Text
+	if len(clue.Parts) > 0 && !clue.IsContinuation() {
+		text = strings.TrimSpace(strings.TrimPrefix(text, reMultiPartPrefix.FindString(text)))
+	}
+	if clue.fitsAnswer(clue.Enumeration) {
+		if match := reEnumeration.FindStringSubmatch(text); match != nil && match[1] == clue.Enumeration {
+			text = strings.TrimSpace(strings.TrimSuffix(text, match[0]))
+		}
+	}
+	return text
+}
+
+// EnumerationText returns the enumeration of the whole answer, falling back
+// to the cell count of each part when the clue doesn't give one that fits.
+func (clue *Clue) EnumerationText() string {
+	if root := clue.Root(); root.fitsAnswer(root.Enumeration) {
+		return root.Enumeration
+	}
+	lengths := make([]string, 0, len(clue.AllParts()))
+	for _, l := range clue.Lengths() {
+		lengths = append(lengths, strconv.Itoa(l))
+	}
+	return strings.Join(lengths, ",")
+}
+
+// WordBreaks returns the separators between the words of the answer, keyed by
+// the index in AllCells of the cell they follow. Enumerations that don't add
+// up to the answer length are ignored.
+func (clue *Clue) WordBreaks() map[int]rune {
+	root := clue.Root()
+	breaks := make(map[int]rune)
+	if !root.fitsAnswer(root.Enumeration) {
+		return breaks
+	}
+
+	idx := 0
+	for _, match := range reEnumerationWord.FindAllStringSubmatch(root.Enumeration, -1) {
+		n, _ := strconv.Atoi(match[1])
+		idx += n
+		if sep := strings.TrimSpace(match[2]); sep != "" {
+			breaks[idx-1] = []rune(sep)[0]
+		} else if match[2] != "" {
+			breaks[idx-1] = ' '
+		}
+	}
+	return breaks
+}
+
+// fitsAnswer reports whether the word lengths of the enumeration add up to
+// the length of the whole answer.
+func (clue *Clue) fitsAnswer(enumeration string) bool {
+	if enumeration == "" {
+		return false
+	}
+	total := 0
+	for _, match := range reEnumerationWord.FindAllStringSubmatch(enumeration, -1) {
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			return false
+		}
+		total += n
+	}
+	return total == len(clue.Root().AllCells())
+}
+
+// Label returns the clue number, joined with the numbers of any other parts,
+// e.g. "17/23" or "5/12D" when a part runs in the other direction.
+func (clue *Clue) Label() string {
+	parts := clue.AllParts()
+	labels := make([]string, len(parts))
+	for i, part := range parts {
+		labels[i] = strconv.Itoa(part.Number)
+		if part.IsVert != parts[0].IsVert {
+			labels[i] += directionLetter(part.IsVert)
+		}
+	}
+	return strings.Join(labels, "/")
+}
+
+func directionLetter(isVert bool) string {
+	if isVert {
+		return "D"
+	}
+	return "A"
+}
+
+// enumeration
+
+var (
+	reEnumeration     = regexp.MustCompile(`\s*\((\d+(?:\s*[,\-.' ]\s*\d+)*)\)\s*$`)
+	reEnumerationWord = regexp.MustCompile(`(\d+)(\D*)`)
+)
+
+// parseEnumeration extracts a trailing enumeration such as "(3,4)" or "(5-3)"
+// from the clue text.
+func parseEnumeration(text string) string {
+	if match := reEnumeration.FindStringSubmatch(text); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// linking
+
+var (
+	clueRefPattern    = `(\d+)(?:-?(across|down|a|d)\b|\s+(across|down)\b)?`
+	reClueRef         = regexp.MustCompile(`(?i)` + clueRefPattern)
+	reMultiPartPrefix = regexp.MustCompile(`(?i)^\s*` + clueRefPattern + `(?:\s*/\s*` + clueRefPattern + `)+\b`)
+	reContinuation    = regexp.MustCompile(`(?i)^\s*see\s+` + clueRefPattern + `\b`)
+)
+
+type clueRef struct {
+	number int
+	isVert bool
+}
+
+// parseClueRefs reads references like "17-Across" or "23" from text. A
+// reference without a direction takes the direction of the one before it.
+func parseClueRefs(text string, isVert bool) []clueRef {
+	refs := make([]clueRef, 0, 2)
+	for _, match := range reClueRef.FindAllStringSubmatch(text, -1) {
+		num, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		switch strings.ToLower(match[2] + match[3]) {
+		case "across", "a":
+			isVert = false
+		case "down", "d":
+			isVert = true
+		}
+		refs = append(refs, clueRef{number: num, isVert: isVert})
+	}
+	return refs
+}
+
+// LinkClues joins clues that continue a single answer across several
+// entries. Heads are recognised by a "17-Across/23-Across ..." prefix and
+// continuations by a "See 17-Across" clue.
+func LinkClues(puz *Puzzle) {
+	answers := make(map[*Clue][]*Clue)
+	order := make([]*Clue, 0)
+
+	addPart := func(head, part *Clue) {
+		parts, ok := answers[head]
+		if !ok {
+			parts = []*Clue{head}
+			order = append(order, head)
+		}
+		for _, p := range parts {
+			if p == part {
+				return
+			}
+		}
+		answers[head] = append(parts, part)
+	}
+
+	for _, clue := range puz.Clues {
+		prefix := reMultiPartPrefix.FindString(clue.Text)
+		if prefix == "" {
+			continue
+		}
+		refs := parseClueRefs(prefix, clue.IsVert)
+		if refs[0].number != clue.Number || refs[0].isVert != clue.IsVert {
+			continue
+		}
+		for _, ref := range refs[1:] {
+			if part := puz.FindClue(ref.number, ref.isVert); part != nil && part != clue {
+				addPart(clue, part)
+			}
+		}
+	}
+
+	for _, clue := range puz.Clues {
+		match := reContinuation.FindString(clue.Text)
+		if match == "" {
+			continue
+		}
+		refs := parseClueRefs(match, clue.IsVert)
+		if head := puz.FindClue(refs[0].number, refs[0].isVert); head != nil && head != clue {
+			addPart(head, clue)
+		}
+	}
+
+outer:
+	for _, head := range order {
+		parts := answers[head]
+		for _, part := range parts {
+			if part.Parts != nil {
+				// already part of another answer
+				continue outer
+			}
+		}
+		for _, part := range parts {
+			part.Parts = parts
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package puzzle
+
+import (
+	"strconv"
+	"strings"
+)
+
+// the character encoding of the strings in a puzzle file
+type TextEncoding int
+
+const (
+	EncodingLatin1 TextEncoding = iota
+	EncodingUTF8
+)
+
+// EncodingForVersion returns the string encoding used by a .puz version.
+// Files from version 2.0 onwards store UTF-8, older files ISO-8859-1.
+func EncodingForVersion(version string) TextEncoding {
+	major, _, _ := strings.Cut(version, ".")
+	if n, err := strconv.Atoi(major); err == nil && n >= 2 {
+		return EncodingUTF8
+	}
+	return EncodingLatin1
+}
+
+// Decode converts raw bytes in the encoding to a string.
+func (e TextEncoding) Decode(raw []byte) string {
+	if e == EncodingUTF8 {
+		return string(raw)
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(raw))
+	for _, b := range raw {
+		sb.WriteRune(rune(b))
+	}
+	return sb.String()
+}
+
+// Encode converts a string back to raw bytes in the encoding. Runes that
+// can't be represented in Latin-1 are replaced with '?'.
+func (e TextEncoding) Encode(s string) []byte {
+	if e == EncodingUTF8 {
+		return []byte(s)
+	}
+
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r > 0xFF {
+			out = append(out, '?')
+		} else {
+			out = append(out, byte(r))
+		}
+	}
+	return out
+}
+
+func (e TextEncoding) String() string {
+	if e == EncodingUTF8 {
+		return "UTF-8"
+	}
+	return "ISO-8859-1"
+}
@@ -0,0 +1,80 @@
+package puzzle
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PuzzleText returns the puzzle as plain text: the grid as it has been filled
+// in, with # for blank squares and _ for empty ones, followed by the clues.
+func PuzzleText(puz *Puzzle) string {
+	var b strings.Builder
+	b.WriteString(puz.Title + "\n")
+	if puz.Author != "" {
+		b.WriteString("by " + puz.Author + "\n")
+	}
+	b.WriteString("\n")
+
+	for y := 0; y < puz.Height; y++ {
+		row := make([]string, puz.Width)
+		for x := range row {
+			cell := puz.CellAt(x, y)
+			switch {
+			case cell.IsBlank():
+				row[x] = "#"
+			case cell.IsEmpty():
+				row[x] = "_"
+			default:
+				row[x] = cell.Input()
+			}
+		}
+		b.WriteString(strings.Join(row, " ") + "\n")
+	}
+
+	for _, list := range []struct {
+		title string
+		clues []*Clue
+	}{{"Across", puz.AcrossClues}, {"Down", puz.DownClues}} {
+		b.WriteString("\n" + list.title + "\n")
+		for _, clue := range list.clues {
+			if clue.IsContinuation() {
+				continue
+			}
+			fmt.Fprintf(&b, "%s. %s (%s)\n", clue.Label(), StripMarkup(clue.DisplayText()), clue.EnumerationText())
+		}
+	}
+	return b.String()
+}
+
+// ExportText writes the puzzle as text next to its file, returning the path
+// written to. An existing file is never overwritten: the name gets a number,
+// as in sunday-1.txt, until it is free.
+func ExportText(puz *Puzzle) (string, error) {
+	if puz.Builder == nil {
+		return "", fmt.Errorf("puzzle has no file")
+	}
+	base := strings.TrimSuffix(puz.Builder.Path(), filepath.Ext(puz.Builder.Path()))
+	for n := 0; ; n++ {
+		path := base + ".txt"
+		if n > 0 {
+			path = fmt.Sprintf("%s-%d.txt", base, n)
+		}
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		_, err = file.WriteString(PuzzleText(puz))
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", err
+		}
+		return path, nil
+	}
+}
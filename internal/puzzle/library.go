@@ -0,0 +1,199 @@
+package puzzle
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// LibraryEntry describes a puzzle file found while scanning the library.
+type LibraryEntry struct {
+	Path     string
+	Title    string
+	Author   string
+	Width    int
+	Height   int
+	Progress float64 // fraction of the grid that is filled in
+	Solved   bool
+
+	// the puzzle's date, from its title or file name, or when it was last
+	// played if it has none, with LastPlayed set. It is the zero time for a
+	// puzzle with neither.
+	Date       time.Time
+	LastPlayed bool
+}
+
+type LibrarySort int
+
+const (
+	SortByDate LibrarySort = iota
+	SortByTitle
+	SortByAuthor
+	SortByProgress
+)
+
+func (s LibrarySort) String() string {
+	switch s {
+	case SortByTitle:
+		return "title"
+	case SortByAuthor:
+		return "author"
+	case SortByProgress:
+		return "progress"
+	}
+	return "date"
+}
+
+// Next returns the sort order that follows s, wrapping around.
+func (s LibrarySort) Next() LibrarySort {
+	return (s + 1) % (SortByProgress + 1)
+}
+
+// IsPuzzleFile reports whether the file has an extension cross can open.
+func IsPuzzleFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".puz":
+		return true
+	}
+	return false
+}
+
+// ScanLibrary walks the directories for puzzle files. Hidden directories are
+// skipped, as are files that fail to load.
+func ScanLibrary(dirs []string) []*LibraryEntry {
+	entries := make([]*LibraryEntry, 0)
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				if path != dir && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !IsPuzzleFile(path) {
+				return nil
+			}
+			if abs, err := filepath.Abs(path); err == nil {
+				if seen[abs] {
+					return nil
+				}
+				seen[abs] = true
+			}
+			if entry, err := NewLibraryEntry(path); err == nil {
+				entries = append(entries, entry)
+			}
+			return nil
+		})
+	}
+	return entries
+}
+
+// NewLibraryEntry reads the puzzle at path and summarises it.
+func NewLibraryEntry(path string) (*LibraryEntry, error) {
+	builder, err := NewBuilderFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	puz, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &LibraryEntry{
+		Path:     path,
+		Title:    puz.Title,
+		Author:   puz.Author,
+		Width:    puz.Width,
+		Height:   puz.Height,
+		Progress: puz.Completion(),
+		Solved:   puz.IsSolved(),
+	}
+	// the progress file is saved with the puzzle, so its time is when it was
+	// last played; a puzzle without one has no date to show
+	entry.Date = findDate(puz.Title, filepath.Base(path))
+	if entry.Date.IsZero() {
+		if progress, err := puz.progressPath(); err == nil {
+			if info, err := os.Stat(progress); err == nil {
+				entry.Date, entry.LastPlayed = info.ModTime(), true
+			}
+		}
+	}
+	return entry, nil
+}
+
+// dateFormats are the ways dates are commonly written in puzzle titles and
+// file names, each tried with its layouts in turn.
+var dateFormats = []struct {
+	re      *regexp.Regexp
+	layouts []string
+}{
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}`), []string{"2006-01-02"}},
+	{regexp.MustCompile(`[A-Z][a-z]+ \d{1,2}, \d{4}`), []string{"Jan 2, 2006", "January 2, 2006"}},
+}
+
+// findDate returns the first date written in the texts, or the zero time.
+func findDate(texts ...string) time.Time {
+	for _, text := range texts {
+		for _, format := range dateFormats {
+			for _, match := range format.re.FindAllString(text, -1) {
+				for _, layout := range format.layouts {
+					if date, err := time.Parse(layout, match); err == nil {
+						return date
+					}
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// FilterLibrary returns the entries whose title, author or file name contain
+// the query, ignoring case.
+func FilterLibrary(entries []*LibraryEntry, query string) []*LibraryEntry {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return entries
+	}
+
+	filtered := make([]*LibraryEntry, 0)
+	for _, entry := range entries {
+		haystack := strings.ToLower(entry.Title + "\x00" + entry.Author + "\x00" + filepath.Base(entry.Path))
+		if strings.Contains(haystack, query) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// SortLibrary sorts the entries in place. Dates and progress sort with the
+// newest and most complete first.
+func SortLibrary(entries []*LibraryEntry, by LibrarySort) {
+	slices.SortStableFunc(entries, func(a, b *LibraryEntry) int {
+		switch by {
+		case SortByTitle:
+			return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		case SortByAuthor:
+			return strings.Compare(strings.ToLower(a.Author), strings.ToLower(b.Author))
+		case SortByProgress:
+			return compareFloat(b.Progress, a.Progress)
+		}
+		return b.Date.Compare(a.Date)
+	})
+}
+
+func compareFloat(a, b float64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,124 @@
+package puzzle
+
+import (
+	"html"
+	"strings"
+)
+
+type SpanStyle uint8
+
+const (
+	SpanItalic SpanStyle = 1 << iota
+	SpanBold
+	SpanStrike
+	SpanSub
+	SpanSup
+)
+
+// a styled run of clue text
+type Span struct {
+	Text  string
+	Style SpanStyle
+}
+
+var markupTags = map[string]SpanStyle{
+	"i":      SpanItalic,
+	"em":     SpanItalic,
+	"b":      SpanBold,
+	"strong": SpanBold,
+	"s":      SpanStrike,
+	"strike": SpanStrike,
+	"del":    SpanStrike,
+	"sub":    SpanSub,
+	"sup":    SpanSup,
+}
+
+// ParseMarkup splits HTML formatted text into styled spans.
+//
+// Only a safe subset of tags is understood (<i>, <b>, <s>, <sub>, <sup> and
+// their common aliases, plus <br>); any other tag is dropped while its text
+// is kept. Entities are decoded in the span text.
+func ParseMarkup(s string) []Span {
+	spans := make([]Span, 0, 1)
+	depth := make(map[SpanStyle]int)
+	var style SpanStyle
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() > 0 {
+			spans = append(spans, Span{Text: html.UnescapeString(text.String()), Style: style})
+			text.Reset()
+		}
+	}
+
+	for len(s) > 0 {
+		start := strings.IndexByte(s, '<')
+		if start == -1 {
+			text.WriteString(s)
+			break
+		}
+		end := strings.IndexByte(s[start:], '>')
+		if end == -1 {
+			text.WriteString(s)
+			break
+		}
+		text.WriteString(s[:start])
+
+		// a '<' that doesn't open a tag, e.g. "a < b > c"
+		if next := s[start+1]; next != '/' && !isASCIILetter(next) {
+			text.WriteByte('<')
+			s = s[start+1:]
+			continue
+		}
+
+		tag := strings.ToLower(strings.TrimSpace(s[start+1 : start+end]))
+		s = s[start+end+1:]
+
+		closing := strings.HasPrefix(tag, "/")
+		tag = strings.TrimPrefix(tag, "/")
+		tag = strings.TrimSuffix(tag, "/")
+		if fields := strings.Fields(tag); len(fields) > 0 {
+			tag = fields[0]
+		}
+
+		if tag == "br" {
+			text.WriteString("\n")
+			continue
+		}
+
+		flag, ok := markupTags[tag]
+		if !ok {
+			continue
+		}
+
+		flush()
+		if closing {
+			if depth[flag] > 0 {
+				depth[flag]--
+			}
+		} else {
+			depth[flag]++
+		}
+		if depth[flag] > 0 {
+			style |= flag
+		} else {
+			style &^= flag
+		}
+	}
+	flush()
+
+	return spans
+}
+
+// StripMarkup returns the plain text of HTML formatted text.
+func StripMarkup(s string) string {
+	var sb strings.Builder
+	for _, span := range ParseMarkup(s) {
+		sb.WriteString(span.Text)
+	}
+	return sb.String()
+}
+
+func isASCIILetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,23 @@
+package puzzle
+
+// CircledCells returns the circled cells in grid order.
+func (puz *Puzzle) CircledCells() []*Cell {
+	cells := make([]*Cell, 0)
+	for _, cell := range puz.Grid {
+		if !cell.IsBlank() && cell.IsCircled() {
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
+
+// CircledCells returns the circled cells of the clue in answer order.
+func (clue *Clue) CircledCells() []*Cell {
+	cells := make([]*Cell, 0)
+	for _, cell := range clue.AllCells() {
+		if cell.IsCircled() {
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
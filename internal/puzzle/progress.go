@@ -0,0 +1,105 @@
+package puzzle
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Progress holds solver state that is never written to the puzzle file.
+type Progress struct {
+	MetaAnswer string            `json:"meta_answer,omitempty"`
+	Notes      map[string]string `json:"notes,omitempty"` // clue notes by Clue.Key
+	NotesSeen  bool              `json:"notes_seen,omitempty"`
+}
+
+func (p Progress) isEmpty() bool {
+	return p.MetaAnswer == "" && len(p.Notes) == 0 && !p.NotesSeen
+}
+
+// ProgressDir returns the directory progress files are kept in,
+// $XDG_DATA_HOME/cross/progress.
+func ProgressDir() (string, error) {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find data directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dir, "cross", "progress"), nil
+}
+
+// progressPath returns the progress file for the puzzle. Puzzles are keyed by
+// their contents so progress follows a file that is moved or renamed.
+func (puz *Puzzle) progressPath() (string, error) {
+	dir, err := ProgressDir()
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha1.New()
+	hash.Write(puz.Solution)
+	hash.Write([]byte(puz.Title + "\x00" + puz.Author))
+	return filepath.Join(dir, hex.EncodeToString(hash.Sum(nil))+".json"), nil
+}
+
+// LoadProgress reads the saved progress for the puzzle, if there is any.
+func (puz *Puzzle) LoadProgress() error {
+	path, err := puz.progressPath()
+	if err != nil {
+		return err
+	}
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read progress: %w", err)
+	}
+
+	if err := json.Unmarshal(raw, &puz.Progress); err != nil {
+		return fmt.Errorf("failed to parse progress file %v: %w", path, err)
+	}
+
+	for _, clue := range puz.Clues {
+		clue.Note = puz.Progress.Notes[clue.Key()]
+	}
+	return nil
+}
+
+// SaveProgress writes the progress for the puzzle. Nothing is written until
+// there is some progress to keep.
+func (puz *Puzzle) SaveProgress() error {
+	path, err := puz.progressPath()
+	if err != nil {
+		return err
+	}
+
+	puz.Progress.Notes = make(map[string]string)
+	for _, clue := range puz.Clues {
+		if clue.Note != "" {
+			puz.Progress.Notes[clue.Key()] = clue.Note
+		}
+	}
+
+	if puz.Progress.isEmpty() {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+	}
+
+	raw, err := json.MarshalIndent(puz.Progress, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode progress: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create progress directory: %w", err)
+	}
+	return os.WriteFile(path, raw, 0644)
+}
@@ -2,6 +2,7 @@ package puzzle
 
 import (
 	"fmt"
+	"unicode"
 )
 
 type Puzzle struct {
@@ -15,11 +16,15 @@ type Puzzle struct {
 	DownClues   []*Clue
 	AcrossClues []*Clue
 	Grid        []*Cell
+	Charset     map[rune]bool
 
 	Title     string
 	Author    string
 	Copyright string
 	Notes     string
+	Format    string
+
+	Progress Progress
 }
 
 func NewPuzzle() *Puzzle {
@@ -53,6 +58,87 @@ func (puz *Puzzle) CellAt(x, y int) *Cell {
 	return puz.Grid[y*puz.Width+x]
 }
 
+// AllowsInput reports whether the rune can be entered into a cell.
+func (puz *Puzzle) AllowsInput(r rune) bool {
+	return unicode.IsPrint(r) && puz.Charset[toUpperLatin1(r)]
+}
+
+// FindClue returns the clue with the given number and direction, or nil.
+func (puz *Puzzle) FindClue(number int, isVert bool) *Clue {
+	clues := puz.AcrossClues
+	if isVert {
+		clues = puz.DownClues
+	}
+	for _, clue := range clues {
+		if clue.Number == number {
+			return clue
+		}
+	}
+	return nil
+}
+
+// PositionOf returns the grid coordinates of the given cell.
+func (puz *Puzzle) PositionOf(cell *Cell) (int, int, bool) {
+	for i, c := range puz.Grid {
+		if c == cell {
+			return i % puz.Width, i / puz.Width, true
+		}
+	}
+	return -1, -1, false
+}
+
 func (puz *Puzzle) Save() {
 	puz.Builder.Write()
+	puz.SaveProgress()
+}
+
+// Completion returns the fraction of the grid that has been filled in.
+func (puz *Puzzle) Completion() float64 {
+	filled, total := 0, 0
+	for _, cell := range puz.Grid {
+		if cell.IsBlank() {
+			continue
+		}
+		total++
+		if !cell.IsEmpty() {
+			filled++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(filled) / float64(total)
+}
+
+// IsSolved reports whether every cell holds the correct answer.
+func (puz *Puzzle) IsSolved() bool {
+	for _, cell := range puz.Grid {
+		if !cell.IsBlank() && !cell.IsCorrect() {
+			return false
+		}
+	}
+	return len(puz.Grid) > 0
+}
+
+// LoadFromFile builds and validates the puzzle at path and loads the
+// solver's saved progress for it.
+func LoadFromFile(path string) (*Puzzle, error) {
+	builder, err := NewBuilderFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	puz, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := builder.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := puz.LoadProgress(); err != nil {
+		return nil, err
+	}
+	return puz, nil
 }
@@ -59,3 +59,16 @@ func (b *Buffer) String() string {
 
 	return sb.String()
 }
+
+// Crop returns the h by w part of the buffer starting at row, col.
+func (b *Buffer) Crop(row, col, h, w int) *Buffer {
+	out := NewBuffer(w, h)
+	for r := range h {
+		for c := range w {
+			if value, err := b.Get(row+r, col+c); err == nil {
+				out.Set(r, c, value)
+			}
+		}
+	}
+	return out
+}
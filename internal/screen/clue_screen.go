@@ -0,0 +1,128 @@
+package screen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/robertcurry0216/cross/common"
+	"github.com/robertcurry0216/cross/internal/keymap"
+	"github.com/robertcurry0216/cross/internal/puzzle"
+	"github.com/robertcurry0216/cross/internal/theme"
+)
+
+const clueScreenMaxWidth int = 70
+
+// ClueScreen shows one clue at a time with its answer boxes and the entries
+// that cross it, for solving without the grid.
+type ClueScreen struct {
+	puzzle *puzzle.Puzzle
+}
+
+func (s *ClueScreen) Init(state common.State) {
+	s.puzzle = state.Puzzle
+}
+
+func (s *ClueScreen) View(state common.State) string {
+	th := state.Theme
+	view := state.PuzzleView
+	w := min(state.Width, clueScreenMaxWidth)
+	textW := uint(max(w-4, 1))
+
+	cell := s.puzzle.CellAt(view.X, view.Y)
+	var clue *puzzle.Clue
+	if cell != nil {
+		if view.IsVert {
+			clue = cell.ClueVert
+		} else {
+			clue = cell.ClueHoriz
+		}
+	}
+
+	sections := make([]string, 0)
+	title := "By clue"
+	if clue != nil {
+		root := clue.Root()
+		title = fmt.Sprintf("%s %s", root.Label(), directionName(root.IsVert))
+
+		text := renderMarkup(root.DisplayText(), th.HighlightClue) + th.HighlightClue.Render(fmt.Sprintf(" (%s)", root.EnumerationText()))
+		sections = append(sections, common.WrapString(text, textW))
+		if root.Note != "" {
+			sections = append(sections, common.WrapString(th.Note.Render(root.Note), textW))
+		}
+		// cut short rather than wrapped, which would break up the cells
+		focused := lipgloss.NewStyle().MaxWidth(int(textW)).Render(renderFocusedClue(th, clue))
+		sections = append(sections, "", focused, "")
+
+		if crossings := renderCrossings(th, clue, cell, textW); crossings != "" {
+			sections = append(sections, th.Title.Render("Crossing:"), crossings, "")
+		}
+	}
+
+	keys := state.Keys
+	help := keymap.JoinHelp(
+		keys.HelpText(keymap.NextUnsolved, ""),
+		keys.HelpText(keymap.PrevUnsolved, ""),
+		keys.HelpText(keymap.Up, "Previous clue"),
+		keys.HelpText(keymap.Down, "Next clue"),
+		keys.HelpText(keymap.Back, "Grid"),
+	)
+	sections = append(sections, th.CellText.Render(common.WrapString(help, textW)))
+
+	box := th.Border.Border(titledBorder(th, title)).BorderForeground(th.Colors.FocusedBorder).Width(w - 2).Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+	return lipgloss.Place(state.Width, state.Height, lipgloss.Center, lipgloss.Center, box)
+}
+
+func directionName(isVert bool) string {
+	if isVert {
+		return "Down"
+	}
+	return "Across"
+}
+
+// renderCrossings lists the entries that cross the answer, each with its
+// letters so far and the crossing square picked out.
+func renderCrossings(th *theme.Theme, clue *puzzle.Clue, selected *puzzle.Cell, w uint) string {
+	lines := make([]string, 0)
+	seen := make(map[*puzzle.Clue]bool)
+	for _, part := range clue.AllParts() {
+		for _, cell := range part.Cells {
+			crossing := cell.ClueHoriz
+			if !part.IsVert {
+				crossing = cell.ClueVert
+			}
+			if crossing == nil || seen[crossing.Root()] {
+				continue
+			}
+			root := crossing.Root()
+			seen[root] = true
+
+			style := th.CellText
+			if cell == selected {
+				style = th.HighlightClue
+			}
+			key := fmt.Sprintf("%-4s", root.Key())
+			text := renderMarkup(root.DisplayText(), style) + style.Render(fmt.Sprintf(" (%s)", root.EnumerationText()))
+			lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, style.Render(key), common.WrapString(text, max(w, 5)-4)))
+			lines = append(lines, "    "+renderPattern(th, root.AllCells(), cell))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderPattern shows the letters of an answer with blanks for empty cells,
+// highlighting the given cell.
+func renderPattern(th *theme.Theme, cells []*puzzle.Cell, highlight *puzzle.Cell) string {
+	letters := make([]string, len(cells))
+	for i, cell := range cells {
+		letter := "_"
+		if !cell.IsEmpty() {
+			letter = cell.Input()
+		}
+		if cell == highlight {
+			letter = th.HighlightCell.Render(letter)
+		}
+		letters[i] = letter
+	}
+	return strings.Join(letters, " ")
+}
@@ -0,0 +1,55 @@
+package screen
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/robertcurry0216/cross/common"
+	"github.com/robertcurry0216/cross/internal/keymap"
+)
+
+const helpMaxWidth int = 70
+
+// HelpScreen lists the active key bindings of every screen.
+type HelpScreen struct{}
+
+func (s *HelpScreen) Init(state common.State) {}
+
+func (s *HelpScreen) View(state common.State) string {
+	th := state.Theme
+	w := min(state.Width-2, helpMaxWidth)
+	keys := state.Keys
+
+	groups := []struct {
+		title   string
+		actions []keymap.Action
+	}{
+		{"Everywhere", keymap.GlobalActions},
+		{"Grid", keymap.PuzzleActions},
+		{"By clue", keymap.ClueActions},
+		{"Library", keymap.LibraryActions},
+	}
+
+	// two groups a row, so the list fits on most screens
+	columns := make([]string, 0, len(groups))
+	for _, group := range groups {
+		lines := []string{th.Title.Render(group.title)}
+		for _, action := range group.actions {
+			binding := keys.Binding(action)
+			if !binding.Enabled() {
+				continue
+			}
+			key := lipgloss.NewStyle().Width(12).Render(binding.Help().Key)
+			lines = append(lines, th.CellText.Render(key)+binding.Help().Desc)
+		}
+		columns = append(columns, lipgloss.NewStyle().Width((w-4)/2).Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+	}
+
+	sections := make([]string, 0)
+	for i := 0; i < len(columns); i += 2 {
+		sections = append(sections, lipgloss.JoinHorizontal(lipgloss.Top, columns[i:min(i+2, len(columns))]...), "")
+	}
+	note := "Keys can be changed in the [keys] table of the config. " + keys.HelpText(keymap.Back, "")
+	sections = append(sections, th.CellText.Render(common.WrapString(note, uint(w-4))))
+
+	box := th.Border.Border(titledBorder(th, "Keys")).Width(w - 2).Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+	return lipgloss.Place(state.Width, state.Height, lipgloss.Center, lipgloss.Center, box)
+}
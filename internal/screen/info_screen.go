@@ -0,0 +1,56 @@
+package screen
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/robertcurry0216/cross/common"
+	"github.com/robertcurry0216/cross/internal/keymap"
+	"github.com/robertcurry0216/cross/internal/puzzle"
+)
+
+const infoMaxWidth int = 70
+
+// InfoScreen shows the puzzle's metadata and notepad.
+type InfoScreen struct {
+	puzzle *puzzle.Puzzle
+}
+
+func (s *InfoScreen) Init(state common.State) {
+	s.puzzle = state.Puzzle
+}
+
+func (s *InfoScreen) View(state common.State) string {
+	th := state.Theme
+	puz := s.puzzle
+	w := min(state.Width-2, infoMaxWidth)
+	textW := uint(w - 4)
+
+	sections := []string{th.Title.Render(common.WrapString(puz.Title, textW))}
+	if puz.Author != "" {
+		sections = append(sections, common.WrapString("by "+puz.Author, textW))
+	}
+	if puz.Copyright != "" {
+		sections = append(sections, th.CellText.Render(common.WrapString(puz.Copyright, textW)))
+	}
+
+	if puz.Notes != "" {
+		sections = append(sections, "", th.Title.Render("Notes:"), common.WrapString(renderMarkup(puz.Notes, th.Note), textW))
+	}
+
+	details := []string{
+		fmt.Sprintf("Size:    %d%s%d", puz.Width, glyph(th, times), puz.Height),
+		fmt.Sprintf("Clues:   %d across, %d down", len(puz.AcrossClues), len(puz.DownClues)),
+		fmt.Sprintf("Format:  %s", puz.Format),
+	}
+	sections = append(sections, "")
+	for _, detail := range details {
+		sections = append(sections, th.CellText.Render(common.WrapString(detail, textW)))
+	}
+
+	help := th.CellText.Render(state.Keys.HelpText(keymap.Back, ""))
+	sections = append(sections, "", help)
+
+	box := th.Border.Border(titledBorder(th, "Puzzle info")).Width(w - 2).Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+	return lipgloss.Place(state.Width, state.Height, lipgloss.Center, lipgloss.Center, box)
+}
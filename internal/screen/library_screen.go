@@ -0,0 +1,173 @@
+package screen
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/robertcurry0216/cross/common"
+	"github.com/robertcurry0216/cross/internal/keymap"
+	"github.com/robertcurry0216/cross/internal/puzzle"
+	"github.com/robertcurry0216/cross/internal/theme"
+)
+
+const (
+	libraryMaxWidth  int = 110
+	libraryDate          = "2006-01-02"
+	libraryPlayedTag     = "last played "
+)
+
+// librarySortColumn is the column each sort order is keyed on.
+var librarySortColumn = map[puzzle.LibrarySort]int{
+	puzzle.SortByTitle:    0,
+	puzzle.SortByAuthor:   1,
+	puzzle.SortByDate:     2,
+	puzzle.SortByProgress: 4,
+}
+
+// LibraryScreen lists the puzzles found in the library directories.
+type LibraryScreen struct {
+	Dirs    []string
+	Entries []*puzzle.LibraryEntry
+	Error   string
+}
+
+func (s *LibraryScreen) Init(state common.State) {
+	if s.Entries == nil {
+		s.Refresh()
+	}
+}
+
+// Refresh rescans the library directories.
+func (s *LibraryScreen) Refresh() {
+	s.Entries = puzzle.ScanLibrary(s.Dirs)
+}
+
+// RefreshEntry reads the puzzle at path again, after it has been played,
+// leaving the rest of the library as it was scanned.
+func (s *LibraryScreen) RefreshEntry(path string) {
+	entry, err := puzzle.NewLibraryEntry(path)
+	if err != nil {
+		return
+	}
+	for i, old := range s.Entries {
+		if old.Path == path {
+			s.Entries[i] = entry
+		}
+	}
+}
+
+// Visible returns the entries that match the filter, in the chosen order.
+func (s *LibraryScreen) Visible(view common.LibraryView) []*puzzle.LibraryEntry {
+	entries := puzzle.FilterLibrary(append([]*puzzle.LibraryEntry(nil), s.Entries...), view.Filter)
+	puzzle.SortLibrary(entries, view.Sort)
+	return entries
+}
+
+func (s *LibraryScreen) View(state common.State) string {
+	th := state.Theme
+	view := state.LibraryView
+	w := min(state.Width-2, libraryMaxWidth)
+	entries := s.Visible(view)
+
+	// title takes whatever the other columns leave
+	dateW := len(libraryDate)
+	for _, entry := range entries {
+		dateW = max(dateW, len(libraryDateText(entry)))
+	}
+	cols := []int{0, max(w/5, 10), dateW, 7, 5}
+	cols[0] = max(w-4-cols[1]-cols[2]-cols[3]-cols[4]-4, 10)
+
+	header := []string{"Title", "Author", "Date", "Size", "Done"}
+	header[librarySortColumn[view.Sort]] += glyph(th, sortMark)
+
+	sections := []string{th.Title.Render(renderLibraryRow(th, header, cols))}
+	if len(entries) == 0 {
+		if len(s.Entries) == 0 {
+			sections = append(sections, th.CellText.Render(common.WrapString("No puzzles found in "+strings.Join(s.Dirs, ", "), uint(w-4))))
+		} else {
+			sections = append(sections, th.CellText.Render("No puzzles match the filter."))
+		}
+	}
+
+	// keep the selected row in view
+	rows := max(state.Height-9, 1)
+	start := max(view.Selected-rows+1, 0)
+	for i := start; i < len(entries) && i < start+rows; i++ {
+		entry := entries[i]
+		title := entry.Title
+		if title == "" {
+			title = filepath.Base(entry.Path)
+		}
+		done := fmt.Sprintf("%3.0f%%", entry.Progress*100)
+		if entry.Solved {
+			done = "  " + glyph(th, checkMark)
+		}
+		row := renderLibraryRow(th, []string{
+			title,
+			entry.Author,
+			libraryDateText(entry),
+			fmt.Sprintf("%d%s%d", entry.Width, glyph(th, times), entry.Height),
+			done,
+		}, cols)
+
+		if i == view.Selected {
+			row = th.HighlightCell.Render(row)
+		}
+		sections = append(sections, row)
+	}
+
+	sections = append(sections, "")
+	if view.Filtering || view.Filter != "" {
+		filter := th.Title.Render("Filter: ") + view.Filter
+		if view.Filtering {
+			filter += th.HighlightCell.Render(" ")
+		}
+		sections = append(sections, filter)
+	}
+	if s.Error != "" {
+		sections = append(sections, lipgloss.NewStyle().Foreground(th.Colors.Error).Render(common.WrapString(s.Error, uint(w-4))))
+	}
+
+	keys := state.Keys
+	help := keymap.JoinHelp(
+		keys.HelpText(keymap.Open, ""),
+		keys.HelpText(keymap.Filter, ""),
+		keys.HelpText(keymap.Sort, fmt.Sprintf("Sort by %v", view.Sort.Next())),
+		keys.HelpText(keymap.Refresh, ""),
+		keys.HelpText(keymap.Help, ""),
+		keys.HelpText(keymap.Back, "Exit"),
+	)
+	if view.Filtering {
+		help = "enter: Done | esc: Clear filter"
+	}
+	sections = append(sections, th.CellText.Render(help))
+
+	title := fmt.Sprintf("Library (%d)", len(entries))
+	box := th.Border.Border(titledBorder(th, title)).Width(w - 2).Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+	return lipgloss.Place(state.Width, state.Height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// libraryDateText is the entry's date, marked when it is only when the
+// puzzle was last played, and blank when it has no date.
+func libraryDateText(entry *puzzle.LibraryEntry) string {
+	if entry.Date.IsZero() {
+		return ""
+	}
+	if entry.LastPlayed {
+		return libraryPlayedTag + entry.Date.Format(libraryDate)
+	}
+	return entry.Date.Format(libraryDate)
+}
+
+// renderLibraryRow lays the fields out in fixed width columns.
+func renderLibraryRow(th *theme.Theme, fields []string, widths []int) string {
+	cells := make([]string, len(fields))
+	for i, field := range fields {
+		field = ansi.Truncate(field, widths[i], ellipsis(th))
+		cells[i] = field + strings.Repeat(" ", widths[i]-ansi.StringWidth(field))
+	}
+	return strings.Join(cells, " ")
+}
@@ -0,0 +1,49 @@
+package screen
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/robertcurry0216/cross/internal/puzzle"
+)
+
+var (
+	subscriptRunes   = map[rune]rune{'0': '₀', '1': '₁', '2': '₂', '3': '₃', '4': '₄', '5': '₅', '6': '₆', '7': '₇', '8': '₈', '9': '₉', '+': '₊', '-': '₋', '=': '₌', '(': '₍', ')': '₎'}
+	superscriptRunes = map[rune]rune{'0': '⁰', '1': '¹', '2': '²', '3': '³', '4': '⁴', '5': '⁵', '6': '⁶', '7': '⁷', '8': '⁸', '9': '⁹', '+': '⁺', '-': '⁻', '=': '⁼', '(': '⁽', ')': '⁾', 'n': 'ⁿ', 'i': 'ⁱ'}
+)
+
+// renderMarkup renders HTML formatted clue text on top of the base style.
+func renderMarkup(text string, base lipgloss.Style) string {
+	var sb strings.Builder
+	for _, span := range puzzle.ParseMarkup(text) {
+		style := base
+		spanText := span.Text
+		if span.Style&puzzle.SpanItalic != 0 {
+			style = style.Italic(true)
+		}
+		if span.Style&puzzle.SpanBold != 0 {
+			style = style.Bold(true)
+		}
+		if span.Style&puzzle.SpanStrike != 0 {
+			style = style.Strikethrough(true)
+		}
+		if span.Style&puzzle.SpanSub != 0 {
+			spanText = mapRunes(spanText, subscriptRunes)
+		}
+		if span.Style&puzzle.SpanSup != 0 {
+			spanText = mapRunes(spanText, superscriptRunes)
+		}
+		sb.WriteString(style.Render(spanText))
+	}
+	return sb.String()
+}
+
+// mapRunes swaps every rune that has a replacement in the table.
+func mapRunes(s string, table map[rune]rune) string {
+	return strings.Map(func(r rune) rune {
+		if mapped, ok := table[r]; ok {
+			return mapped
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,87 @@
+package screen
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/robertcurry0216/cross/common"
+	"github.com/robertcurry0216/cross/internal/puzzle"
+	"github.com/robertcurry0216/cross/internal/theme"
+)
+
+const metaMaxWidth int = 60
+
+// MetaScreen collects the circled letters of the puzzle and lets the solver
+// enter an answer to the meta. Puzzle files don't carry the meta's solution,
+// so the answer is only kept, never checked.
+type MetaScreen struct {
+	puzzle *puzzle.Puzzle
+	Answer string
+}
+
+func (s *MetaScreen) Init(state common.State) {
+	s.puzzle = state.Puzzle
+	s.Answer = state.Puzzle.Progress.MetaAnswer
+}
+
+// CapturesInput is always true, as every key goes to the answer.
+func (s *MetaScreen) CapturesInput() bool {
+	return true
+}
+
+func (s *MetaScreen) View(state common.State) string {
+	th := state.Theme
+	w := min(state.Width-2, metaMaxWidth)
+	circled := s.puzzle.CircledCells()
+
+	var sections []string
+	if len(circled) == 0 {
+		sections = append(sections, th.CellText.Render("This puzzle has no circled letters."))
+	} else {
+		sections = append(sections,
+			th.Title.Render("In grid order:"),
+			common.WrapString(renderCircledLetters(circled), uint(w-4)),
+			"",
+			th.Title.Render("By entry:"),
+			renderCircledEntries(th, s.puzzle),
+		)
+	}
+
+	answer := s.Answer + th.HighlightCell.Render(" ")
+	sections = append(sections, "", th.Title.Render("Meta answer: ")+answer)
+
+	help := th.CellText.Render("type: Enter answer | esc: Back")
+	sections = append(sections, "", help)
+
+	box := th.Border.Border(titledBorder(th, "Circled letters")).Width(w - 2).Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+	return lipgloss.Place(state.Width, state.Height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// renderCircledLetters shows the entered letters of the cells, with blanks
+// for the empty ones.
+func renderCircledLetters(cells []*puzzle.Cell) string {
+	letters := make([]string, len(cells))
+	for i, cell := range cells {
+		letters[i] = "_"
+		if !cell.IsEmpty() {
+			letters[i] = cell.Input()
+		}
+	}
+	return strings.Join(letters, " ")
+}
+
+func renderCircledEntries(th *theme.Theme, puz *puzzle.Puzzle) string {
+	lines := make([]string, 0)
+	for _, clues := range [][]*puzzle.Clue{puz.AcrossClues, puz.DownClues} {
+		for _, clue := range clues {
+			if clue.IsContinuation() {
+				continue
+			}
+			if cells := clue.CircledCells(); len(cells) > 0 {
+				key := lipgloss.NewStyle().Width(5).Render(clue.Key())
+				lines = append(lines, th.CellText.Render(key)+renderCircledLetters(cells))
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,160 @@
+package screen
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/robertcurry0216/cross/common"
+	"github.com/robertcurry0216/cross/internal/keymap"
+	"github.com/robertcurry0216/cross/internal/puzzle"
+)
+
+const paletteMaxWidth int = 70
+
+var paletteClueRe = regexp.MustCompile(`^(\d+)\s*-?\s*([a-z]*)$`)
+
+// PaletteItem is a command in the palette: an action to run, or a clue to
+// jump to.
+type PaletteItem struct {
+	Title  string
+	Hint   string
+	Action keymap.Action
+	Clue   *puzzle.Clue
+}
+
+// PaletteScreen finds actions and clues by typing part of their name, a clue
+// number like 23d, or words from a clue.
+type PaletteScreen struct {
+	puzzle   *puzzle.Puzzle
+	Query    string
+	Selected int
+}
+
+func (s *PaletteScreen) Init(state common.State) {
+	s.puzzle = state.Puzzle
+}
+
+// CapturesInput is always true, as every key goes to the query.
+func (s *PaletteScreen) CapturesInput() bool {
+	return true
+}
+
+// Items returns what matches the query, best first.
+func (s *PaletteScreen) Items(state common.State) []PaletteItem {
+	query := strings.ToLower(strings.TrimSpace(s.Query))
+	items := make([]PaletteItem, 0)
+
+	// clue numbers, with or without a direction
+	if match := paletteClueRe.FindStringSubmatch(query); match != nil {
+		number, _ := strconv.Atoi(match[1])
+		for _, isVert := range []bool{false, true} {
+			if !strings.HasPrefix(strings.ToLower(directionName(isVert)), match[2]) {
+				continue
+			}
+			if clue := s.puzzle.FindClue(number, isVert); clue != nil {
+				items = append(items, paletteClueItem(clue))
+			}
+		}
+	}
+
+	type scoredItem struct {
+		item  PaletteItem
+		score int
+	}
+	scored := make([]scoredItem, 0)
+	for _, action := range keymap.PaletteActions {
+		help := state.Keys.Binding(action).Help()
+		if score, ok := common.FuzzyMatch(query, help.Desc); ok {
+			item := PaletteItem{Title: help.Desc, Action: action}
+			if state.Keys.Binding(action).Enabled() {
+				item.Hint = help.Key
+			}
+			scored = append(scored, scoredItem{item, score})
+		}
+	}
+
+	// clues need every word of the query, as most letters appear in most clues
+	if query != "" {
+		words := strings.Fields(query)
+		for _, clues := range [][]*puzzle.Clue{s.puzzle.AcrossClues, s.puzzle.DownClues} {
+			for _, clue := range clues {
+				if clue.IsContinuation() {
+					continue
+				}
+				text := strings.ToLower(puzzle.StripMarkup(clue.DisplayText()))
+				if !containsAll(text, words) {
+					continue
+				}
+				score, _ := common.FuzzyMatch(query, text)
+				scored = append(scored, scoredItem{paletteClueItem(clue), score})
+			}
+		}
+	}
+
+	slices.SortStableFunc(scored, func(a, b scoredItem) int {
+		return b.score - a.score
+	})
+	for _, s := range scored {
+		items = append(items, s.item)
+	}
+	return items
+}
+
+func paletteClueItem(clue *puzzle.Clue) PaletteItem {
+	root := clue.Root()
+	title := root.Label() + " " + directionName(root.IsVert) + ": " + puzzle.StripMarkup(root.DisplayText())
+	return PaletteItem{Title: title, Hint: "Go to", Clue: clue}
+}
+
+func containsAll(text string, words []string) bool {
+	for _, word := range words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *PaletteScreen) View(state common.State) string {
+	th := state.Theme
+	w := min(state.Width-2, paletteMaxWidth)
+	textW := w - 4
+	items := s.Items(state)
+
+	sections := []string{th.Title.Render("> ") + s.Query + th.HighlightCell.Render(" "), ""}
+	if len(items) == 0 {
+		sections = append(sections, th.CellText.Render("Nothing matches."))
+	}
+
+	// keep the selected item in view
+	rows := max(state.Height-8, 1)
+	start := max(s.Selected-rows+1, 0)
+	for i := start; i < len(items) && i < start+rows; i++ {
+		item := items[i]
+		title := ansi.Truncate(item.Title, textW-lipgloss.Width(item.Hint)-1, ellipsis(th))
+		pad := strings.Repeat(" ", max(textW-lipgloss.Width(title)-lipgloss.Width(item.Hint), 1))
+		// the selected row is highlighted as a whole, hint and all
+		if i == s.Selected {
+			sections = append(sections, th.HighlightCell.Render(title+pad+item.Hint))
+		} else {
+			sections = append(sections, title+pad+th.CellText.Render(item.Hint))
+		}
+	}
+
+	keys := state.Keys
+	help := keymap.JoinHelp(
+		keys.HelpText(keymap.Open, "Run"),
+		keys.HelpText(keymap.Up, "Previous"),
+		keys.HelpText(keymap.Down, "Next"),
+		keys.HelpText(keymap.Back, "Close"),
+		"23d: Go to clue",
+	)
+	sections = append(sections, "", th.CellText.Render(common.WrapString(help, uint(textW))))
+
+	box := th.Border.Border(titledBorder(th, "Commands")).BorderForeground(th.Colors.FocusedBorder).Width(w - 2).Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+	return lipgloss.Place(state.Width, state.Height, lipgloss.Center, lipgloss.Center, box)
+}
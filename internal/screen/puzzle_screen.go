@@ -2,31 +2,30 @@ package screen
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/robertcurry0216/cross/common"
+	"github.com/robertcurry0216/cross/internal/config"
+	"github.com/robertcurry0216/cross/internal/keymap"
 	"github.com/robertcurry0216/cross/internal/puzzle"
+	"github.com/robertcurry0216/cross/internal/theme"
 )
 
 const (
-	cellNumberString     = "\u2080\u2081\u2082\u2083\u2084\u2085\u2086\u2087\u2088\u2089"
-	boxString            = "┏┓┗┛━┃┣┫┳┻╋ .*"
-	cellWidth        int = 3
-	gridMinWidth     int = 60
-	clueMaxWidth     int = 80
-	clueMinWidth     int = 50
-)
-
-var (
-	colorHighlightBG,
-	colorHighlightFG,
-	colorError,
-	colorCorrect,
-	colorStatusBar,
-	colorFocusedBorder,
-	colorGridLine lipgloss.AdaptiveColor
+	boxString           = "┏┓┗┛━┃┣┫┳┻╋ .*○✎✓╱▾×"
+	asciiBoxString      = "++++-|+++++ .*o*+/*x"
+	cellWidth       int = 3
+	statusHeight    int = 1
+	clueBarHeight   int = 3
+	clueColumnMin   int = 30
+	clueColumnWidth int = 36
+	stackedCluesMin int = 8
+	clueMaxWidth    int = 80
+	clueMinWidth    int = 50
 )
 
 const (
@@ -44,19 +43,17 @@ const (
 	blank
 	empty
 	emptySelected
+	circle
+	noteMark
+	checkMark
+	wrongMark
+	sortMark
+	times
 )
 
-var cellNumberRunes []string
 var boxRunes []string
-var (
-	styleTitle,
-	styleCellText,
-	styleBorder,
-	styleGridLine,
-	styleHighlightClue,
-	styleHighlightCell,
-	styleCellPadding lipgloss.Style
-)
+var asciiBoxRunes []string
+var styleCellPadding = lipgloss.NewStyle().Width(cellWidth).Align(lipgloss.Center)
 
 // init function to initialize boxRunes
 func init() {
@@ -65,36 +62,43 @@ func init() {
 		boxRunes = append(boxRunes, string(r))
 	}
 
-	cellNumberRunes = make([]string, 0, len(cellNumberString))
-	for _, r := range cellNumberString {
-		cellNumberRunes = append(cellNumberRunes, string(r))
+	asciiBoxRunes = make([]string, 0, len(asciiBoxString))
+	for _, r := range asciiBoxString {
+		asciiBoxRunes = append(asciiBoxRunes, string(r))
 	}
+}
 
-	// colors
-	colorError = lipgloss.AdaptiveColor{Light: "9", Dark: "1"}
-	colorCorrect = lipgloss.AdaptiveColor{Light: "2", Dark: "10"}
-	colorGridLine = lipgloss.AdaptiveColor{Light: "241", Dark: "7"}
-	colorStatusBar = lipgloss.AdaptiveColor{Light: "4", Dark: "12"}
-	colorHighlightBG = lipgloss.AdaptiveColor{Light: "8", Dark: "250"}
-	colorHighlightFG = lipgloss.AdaptiveColor{Light: "15", Dark: "0"}
-	colorFocusedBorder = lipgloss.AdaptiveColor{Light: "2", Dark: "10"}
+// glyph returns one of the box drawing characters, or its ASCII stand-in
+// in an ASCII theme.
+func glyph(th *theme.Theme, i int) string {
+	if th.ASCII {
+		return asciiBoxRunes[i]
+	}
+	return boxRunes[i]
+}
 
-	// styles
-	styleBorder = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
-	styleGridLine = lipgloss.NewStyle().Foreground(colorGridLine)
-	styleTitle = lipgloss.NewStyle().Bold(true)
-	styleCellText = lipgloss.NewStyle().Faint(true)
-	styleHighlightClue = lipgloss.NewStyle().Faint(false).Bold(true)
-	styleHighlightCell = lipgloss.NewStyle().Faint(false).Bold(true).Background(colorHighlightBG).Foreground(colorHighlightFG)
-	styleCellPadding = lipgloss.NewStyle().Width(cellWidth).Align(lipgloss.Center)
+// ellipsis marks text that was cut short.
+func ellipsis(th *theme.Theme) string {
+	if th.ASCII {
+		return "..."
+	}
+	return "…"
 }
 
 type PuzzleScreen struct {
 	puzzle *puzzle.Puzzle
+	vim    bool
 }
 
 func (s *PuzzleScreen) Init(state common.State) {
 	s.puzzle = state.Puzzle
+	s.vim = state.Config.General.Input == config.InputVim
+}
+
+// CapturesInput is true in the vim input mode, where esc changes mode and :q
+// goes back.
+func (s *PuzzleScreen) CapturesInput() bool {
+	return s.vim
 }
 
 func (s *PuzzleScreen) View(state common.State) string {
@@ -111,49 +115,246 @@ func (s *PuzzleScreen) View(state common.State) string {
 
 	layout := calculateLayout(&state)
 
-	return renderPuzzleView(layout, s.puzzle, clue)
+	view, _ := renderPuzzleView(state.Theme, layout, s.puzzle, clue, puzState, state.Keys, s.vim)
+	return view
+}
+
+// PuzzleHit is what is under a point on the screen.
+type PuzzleHit struct {
+	// the grid cell, nil if the point isn't on one
+	Cell *puzzle.Cell
+	X, Y int
+
+	// the clue on the line, nil between clues
+	Clue    *puzzle.Clue
+	InClues bool
+
+	// the clue panel's scroll offset, after keeping it in range
+	ClueScroll int
+}
+
+// HitTest finds the cell or clue drawn at x, y, as the view was last drawn.
+func (s *PuzzleScreen) HitTest(state common.State, x, y int) PuzzleHit {
+	puzState := state.PuzzleView
+	var clue *puzzle.Clue
+	if cell := s.puzzle.CellAt(puzState.X, puzState.Y); cell != nil {
+		clue = cell.ClueHoriz
+		if puzState.IsVert {
+			clue = cell.ClueVert
+		}
+	}
+
+	_, hits := renderPuzzleView(state.Theme, calculateLayout(&state), s.puzzle, clue, puzState, state.Keys, s.vim)
+	var hit PuzzleHit
+
+	// in full, cells are cellWidth wide with a grid line either side, and a
+	// line high with a grid line above and below
+	gx, gy := x-hits.gridX, y-hits.gridY
+	gridW, gridH := hits.grid.size()
+	cx, cy, onCell := gx, gy, gx >= 0 && gy >= 0 && gx < gridW && gy < gridH
+	if !hits.grid.compact {
+		cx, cy = gx/(cellWidth+1), gy/2
+		onCell = onCell && gx%(cellWidth+1) != 0 && gy%2 != 0
+	}
+	if onCell {
+		cx, cy = cx+hits.grid.x, cy+hits.grid.y
+		if cell := s.puzzle.CellAt(cx, cy); !puzzle.IsCellBlankOrNil(cell) {
+			hit.Cell, hit.X, hit.Y = cell, cx, cy
+		}
+	}
+
+	for _, panel := range hits.clues {
+		line := y - panel.y
+		if x >= panel.x && x < panel.x+panel.w && line >= 0 && line < panel.h {
+			hit.InClues = true
+			hit.ClueScroll = panel.scroll
+			if line < len(panel.lines) {
+				hit.Clue = panel.lines[line]
+			}
+		}
+	}
+
+	return hit
 }
 
 // Helpers
 
-func titledBorder(title string) lipgloss.Border {
-	border := lipgloss.RoundedBorder()
-	border.Top = fmt.Sprintf("─%s%s", title, strings.Repeat("─", 500))
+func titledBorder(th *theme.Theme, title string) lipgloss.Border {
+	border := th.Border.GetBorderStyle()
+	border.Top = fmt.Sprintf("%s%s%s", border.Top, title, strings.Repeat(border.Top, 500))
 	return border
 }
 
+// placeBoxes lays the rendered boxes out on a screen height lines high. The
+// boxes tile the screen, so each line is the boxes it crosses, left to right.
+func placeBoxes(height int, boxes []common.LayoutBox, rendered []string) string {
+	lines := make([]string, height)
+	order := make([]int, len(boxes))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int { return boxes[a].X - boxes[b].X })
+
+	for _, i := range order {
+		box := boxes[i]
+		for j, line := range strings.Split(rendered[i], "\n") {
+			if y := box.Y + j; y >= 0 && y < height && j < box.H {
+				lines[y] += line + strings.Repeat(" ", max(box.W-ansi.StringWidth(line), 0))
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// puzzleViewHits records where things were drawn, for mapping the mouse
+// back to them.
+type puzzleViewHits struct {
+	// top left corner of the grid, and the part of it drawn
+	gridX, gridY int
+	grid         gridView
+
+	clues []cluePanelHits
+}
+
+// cluePanelHits is the inside of a clue panel, and the clue shown on each of
+// its lines.
+type cluePanelHits struct {
+	x, y, w, h int
+	lines      []*puzzle.Clue
+	scroll     int
+}
+
 type puzzleViewLayout struct {
 	layout common.LayoutType
+	mode   config.LayoutMode
 	puzzle common.LayoutBox
-	clues  common.LayoutBox
+	clues  []cluePanel
+	bar    common.LayoutBox
 	status common.LayoutBox
+	grid   gridView
+}
+
+// cluePanel is a box of clues, with the sets of clues listed in it.
+type cluePanel struct {
+	box   common.LayoutBox
+	title string
+	sets  []clueSet
+}
+
+type clueSet struct {
+	title string
+	clues []*puzzle.Clue
+}
+
+// gridView is the part of the grid that fits in the puzzle box, and how it
+// is drawn.
+type gridView struct {
+	compact bool
+	// first cell shown, and the number of cells shown each way
+	x, y int
+	w, h int
+}
+
+// size is the width and height of the drawn grid.
+func (g gridView) size() (int, int) {
+	if g.compact {
+		return g.w, g.h
+	}
+	return g.w*(cellWidth+1) + 1, g.h*2 + 1
 }
 
 func calculateLayout(state *common.State) puzzleViewLayout {
 	layout := puzzleViewLayout{}
 	layout.layout = state.PuzzleView.Layout
-
-	// column widths
-	leftColMin := state.Puzzle.Width * 4
-	leftCol := int(float64(state.Width) * 0.6)
-	if leftCol < leftColMin {
-		leftCol = leftColMin
+	puz := state.Puzzle
+
+	// everything but the status bar
+	width, height := state.Width, state.Height-statusHeight
+	layout.status = common.LayoutBox{Y: height, W: width, H: statusHeight}
+
+	// the grid's box, if it had the whole screen
+	natural := fitGrid(common.LayoutBox{W: width, H: height}, puz, state.PuzzleView, state.Config.Display.Grid)
+	gridW, gridH := natural.size()
+	gridW, gridH = gridW+2, gridH+2
+	layout.mode = chooseLayout(state.Config.Display.Layout, width, height, gridW, gridH)
+
+	bothSets := []clueSet{{"Across:", puz.AcrossClues}, {"Down:", puz.DownClues}}
+	switch layout.mode {
+	case config.LayoutColumns:
+		leftCol := min(max(gridW, int(float64(width)*0.4)), width-2*clueColumnMin)
+		across := (width - leftCol) / 2
+		layout.puzzle = common.LayoutBox{W: leftCol, H: height}
+		layout.clues = []cluePanel{
+			{common.LayoutBox{X: leftCol, W: across, H: height}, "Across", []clueSet{{"", puz.AcrossClues}}},
+			{common.LayoutBox{X: leftCol + across, W: width - leftCol - across, H: height}, "Down", []clueSet{{"", puz.DownClues}}},
+		}
+	case config.LayoutStacked:
+		gridBox := max(min(gridH, height-stackedCluesMin), 3)
+		layout.puzzle = common.LayoutBox{W: width, H: gridBox}
+		layout.clues = []cluePanel{{common.LayoutBox{Y: gridBox, W: width, H: height - gridBox}, "Clues", bothSets}}
+	case config.LayoutBar, config.LayoutZen:
+		layout.puzzle = common.LayoutBox{W: width, H: height}
+	default:
+		// column widths, giving the grid room to be drawn in full when the
+		// clues can spare it
+		leftCol := int(float64(width) * 0.6)
+		fullW, fullH := gridView{w: puz.Width, h: puz.Height}.size()
+		fitsFull := fullW+2 <= width-20 && fullH+2 <= height
+		if leftCol < fullW+2 && (fitsFull || state.Config.Display.Grid == config.GridFull) {
+			leftCol = max(leftCol, min(fullW+2, width-20))
+		}
+		layout.puzzle = common.LayoutBox{W: leftCol, H: height}
+		layout.clues = []cluePanel{{common.LayoutBox{X: leftCol, W: width - leftCol, H: height}, "Clues", bothSets}}
 	}
-	rightCol := state.Width - leftCol
-	if rightCol < 20 {
-		rightCol = 20
+
+	// the current clue over the grid, unless the grid would be left too small
+	showBar := state.Config.Display.ClueBar && layout.mode != config.LayoutZen && layout.puzzle.H-clueBarHeight >= 5
+	if showBar || layout.mode == config.LayoutBar {
+		layout.bar = common.LayoutBox{X: layout.puzzle.X, Y: layout.puzzle.Y, W: layout.puzzle.W, H: clueBarHeight}
+		layout.puzzle.Y += clueBarHeight
+		layout.puzzle.H -= clueBarHeight
 	}
+	layout.grid = fitGrid(layout.puzzle, puz, state.PuzzleView, state.Config.Display.Grid)
 
-	// heights
-	statusHeight := 1
-	cluesHeight := state.Height - statusHeight
+	return layout
+}
 
-	// boxes
-	layout.puzzle = common.LayoutBox{W: leftCol, H: state.Height - statusHeight}
-	layout.status = common.LayoutBox{W: state.Width, H: statusHeight}
-	layout.clues = common.LayoutBox{W: rightCol, H: cluesHeight}
+// chooseLayout picks the layout for the screen when it is left to "auto":
+// clues beside a grid gridW by gridH when there is room, two columns of them
+// when there is plenty, under it on tall screens, and only the current clue
+// when nothing else fits.
+func chooseLayout(mode config.LayoutMode, width, height, gridW, gridH int) config.LayoutMode {
+	if mode != config.LayoutAuto && mode != "" {
+		return mode
+	}
+	switch {
+	case width-gridW >= 2*clueColumnWidth:
+		return config.LayoutColumns
+	case width-gridW >= clueColumnMin:
+		return config.LayoutSide
+	case height-gridH >= stackedCluesMin:
+		return config.LayoutStacked
+	}
+	return config.LayoutBar
+}
 
-	return layout
+// fitGrid picks how to draw the grid in the box. The full grid is used when
+// it fits, or always when asked for, else one column a cell. Whatever doesn't
+// fit scrolls to keep the selected cell in view.
+func fitGrid(box common.LayoutBox, puz *puzzle.Puzzle, view common.PuzzleView, mode config.GridMode) gridView {
+	innerW, innerH := box.W-2, box.H-2
+	full := gridView{w: puz.Width, h: puz.Height}
+	fullW, fullH := full.size()
+
+	grid := gridView{compact: true, w: min(puz.Width, innerW), h: min(puz.Height, innerH)}
+	if mode == config.GridFull || (mode == config.GridAuto && fullW <= innerW && fullH <= innerH) {
+		grid = gridView{w: min(puz.Width, (innerW-1)/(cellWidth+1)), h: min(puz.Height, (innerH-1)/2)}
+	}
+	grid.w, grid.h = max(grid.w, 1), max(grid.h, 1)
+
+	grid.x, _, _ = scrollWindow(puz.Width, grid.w, view.X, 0)
+	grid.y, _, _ = scrollWindow(puz.Height, grid.h, view.Y, 0)
+	return grid
 }
 
 // Drawing functions
@@ -164,42 +365,112 @@ func calculateLayout(state *common.State) puzzleViewLayout {
 // | |   | |_| |/ / / /| |  __/ | |__| | |  | | (_| |
 // |_|    \__,_/___/___|_|\___|  \_____|_|  |_|\__,_|
 
-func renderPuzzleView(layout puzzleViewLayout, puzzle *puzzle.Puzzle, clue *puzzle.Clue) string {
+func renderPuzzleView(th *theme.Theme, layout puzzleViewLayout, puzzle *puzzle.Puzzle, clue *puzzle.Clue, view common.PuzzleView, keys keymap.KeyMap, vim bool) (string, puzzleViewHits) {
+	var hits puzzleViewHits
+	boxes := []common.LayoutBox{layout.puzzle}
+
 	// render boxes
-	grid := renderPuzzle(layout.puzzle, puzzle, clue, layout.layout == common.LayoutPuzzleFocus)
-	if lipgloss.Width(grid) < gridMinWidth {
-		grid = lipgloss.PlaceHorizontal(gridMinWidth, lipgloss.Center, grid)
+	grid := renderPuzzle(th, layout.puzzle, layout.grid, puzzle, clue, layout.layout == common.LayoutPuzzleFocus)
+	rendered := []string{grid}
+	gridW, gridH := layout.grid.size()
+	hits.grid = layout.grid
+	hits.gridX = layout.puzzle.X + 1 + max(layout.puzzle.W-2-gridW, 0)/2
+	hits.gridY = layout.puzzle.Y + 1 + max(layout.puzzle.H-2-gridH, 0)/2
+
+	for _, panel := range layout.clues {
+		clues, panelHits := renderClues(th, panel, clue, layout.layout == common.LayoutClueFocus, view)
+		boxes = append(boxes, panel.box)
+		rendered = append(rendered, clues)
+		hits.clues = append(hits.clues, panelHits)
+	}
+
+	if layout.bar.H > 0 {
+		bar, barClues := renderClueBar(th, layout.bar, clue, puzzle.CellAt(view.X, view.Y))
+		boxes = append(boxes, layout.bar)
+		rendered = append(rendered, bar)
+		hits.clues = append(hits.clues, cluePanelHits{x: layout.bar.X + 1, y: layout.bar.Y, w: layout.bar.W - 2, h: layout.bar.H, lines: barClues})
 	}
 
 	// status bar
-	status := renderStatusBar(layout.status)
+	status := renderStatusBar(th, layout.status, keys)
+	if view.Message != "" {
+		status = lipgloss.NewStyle().Width(layout.status.W).MaxWidth(layout.status.W).Render(view.Message)
+	} else if vim {
+		status = renderVimStatusBar(th, layout.status, view.Vim)
+	}
+	boxes = append(boxes, layout.status)
+	rendered = append(rendered, status)
+
+	return placeBoxes(layout.status.Y+layout.status.H, boxes, rendered), hits
+}
 
-	// combine
-	rightColumn := renderClues(layout.clues, puzzle, clue, layout.layout == common.LayoutClueFocus)
-	leftColumn := lipgloss.JoinVertical(lipgloss.Left, grid)
+// renderClueBar shows the selected clue in full, then the clue crossing it
+// at the selected cell with the letters it has so far. The clue on each line
+// is returned too.
+func renderClueBar(th *theme.Theme, box common.LayoutBox, clue *puzzle.Clue, cell *puzzle.Cell) (string, []*puzzle.Clue) {
+	w := box.W - 2
+	var lines []string
+	var clues []*puzzle.Clue
+
+	if clue != nil {
+		root := clue.Root()
+		label := th.Title.Render(fmt.Sprintf("%s %s ", root.Label(), directionName(root.IsVert)))
+		text := renderMarkup(root.DisplayText(), th.HighlightClue) + th.HighlightClue.Render(fmt.Sprintf(" (%s)", root.EnumerationText()))
+		wrapped := strings.Split(common.WrapString(label+text, uint(w)), "\n")
+
+		// leave a line for the crossing clue
+		if room := max(box.H-1, 1); len(wrapped) > room {
+			wrapped = wrapped[:room]
+			tail := ellipsis(th)
+			wrapped[room-1] = ansi.Truncate(wrapped[room-1], w-lipgloss.Width(tail), "") + tail
+		}
+		for _, line := range wrapped {
+			lines = append(lines, line)
+			clues = append(clues, root)
+		}
+	}
 
-	screen := lipgloss.JoinHorizontal(lipgloss.Top, leftColumn, rightColumn)
-	screen = lipgloss.JoinVertical(lipgloss.Center, screen, status)
+	if cell != nil {
+		crossing := cell.ClueVert
+		if clue != nil && clue.IsVert {
+			crossing = cell.ClueHoriz
+		}
+		if crossing != nil && !crossing.SameAnswer(clue) && len(lines) < box.H {
+			root := crossing.Root()
+			label := th.CellText.Render(fmt.Sprintf("%s %s ", root.Label(), directionName(root.IsVert)))
+			text := renderMarkup(root.DisplayText(), th.CellText) + th.CellText.Render(fmt.Sprintf(" (%s)", root.EnumerationText()))
+			lines = append(lines, ansi.Truncate(label+renderPattern(th, root.AllCells(), cell)+"  "+text, w, ellipsis(th)))
+			clues = append(clues, root)
+		}
+	}
 
-	return screen
+	return lipgloss.NewStyle().Padding(0, 1).Width(box.W).Height(box.H).Render(strings.Join(lines, "\n")), clues
 }
 
-func renderPuzzle(box common.LayoutBox, puz *puzzle.Puzzle, selectedClue *puzzle.Clue, focus bool) string {
-	buffer := NewBuffer(puz.Width*2+1, puz.Height*2+1)
-	style := lipgloss.NewStyle().Border(titledBorder(puz.Title)).Height(box.H-2).Width(box.W-2).Align(lipgloss.Center, lipgloss.Center)
+// renderPuzzle draws the part of the grid in view.
+func renderPuzzle(th *theme.Theme, box common.LayoutBox, view gridView, puz *puzzle.Puzzle, selectedClue *puzzle.Clue, focus bool) string {
+	style := lipgloss.NewStyle().Border(titledBorder(th, puz.Title)).Height(box.H-2).Width(box.W-2).Align(lipgloss.Center, lipgloss.Center)
 
 	if focus {
-		style = style.BorderForeground(colorFocusedBorder)
+		style = style.BorderForeground(th.Colors.FocusedBorder)
 	}
 
-	insertCorners(puz, buffer)
-	insertEdges(puz, buffer)
-	insertCells(puz, buffer, selectedClue)
+	if view.compact {
+		buffer := NewBuffer(puz.Width, puz.Height)
+		insertCompactCells(th, puz, buffer, selectedClue)
+		return style.Render(buffer.Crop(view.y, view.x, view.h, view.w).String())
+	}
 
-	return style.Render(buffer.String())
+	buffer := NewBuffer(puz.Width*2+1, puz.Height*2+1)
+	insertCorners(th, puz, buffer)
+	insertEdges(th, puz, buffer)
+	insertWordBreaks(th, puz, buffer)
+	insertCells(th, puz, buffer, selectedClue)
+
+	return style.Render(buffer.Crop(view.y*2, view.x*2, view.h*2+1, view.w*2+1).String())
 }
 
-func insertCorners(puz *puzzle.Puzzle, buffer *Buffer) {
+func insertCorners(th *theme.Theme, puz *puzzle.Puzzle, buffer *Buffer) {
 	var emptyBR, emptyTR, emptyBL, emptyTL bool
 	for y := 0; y < puz.Height+1; y++ {
 		rIdx := y * 2
@@ -214,34 +485,34 @@ func insertCorners(puz *puzzle.Puzzle, buffer *Buffer) {
 
 			switch {
 			case (!emptyTL && !emptyBR) || (!emptyTR && !emptyBL):
-				cell = boxRunes[cross]
+				cell = glyph(th, cross)
 			case emptyTL && emptyTR && !emptyBR && emptyBL:
-				cell = boxRunes[topLeft]
+				cell = glyph(th, topLeft)
 			case emptyTL && emptyTR && emptyBR && !emptyBL:
-				cell = boxRunes[topRight]
+				cell = glyph(th, topRight)
 			case emptyTL && !emptyTR && emptyBR && emptyBL:
-				cell = boxRunes[bottomLeft]
+				cell = glyph(th, bottomLeft)
 			case !emptyTL && emptyTR && emptyBR && emptyBL:
-				cell = boxRunes[bottomRight]
+				cell = glyph(th, bottomRight)
 			case !emptyTL && emptyTR && emptyBR && !emptyBL:
-				cell = boxRunes[rightEdge]
+				cell = glyph(th, rightEdge)
 			case emptyTL && !emptyTR && !emptyBR && emptyBL:
-				cell = boxRunes[leftEdge]
+				cell = glyph(th, leftEdge)
 			case !emptyTL && !emptyTR && emptyBR && emptyBL:
-				cell = boxRunes[bottomEdge]
+				cell = glyph(th, bottomEdge)
 			case emptyTL && emptyTR && !emptyBR && !emptyBL:
-				cell = boxRunes[topEdge]
+				cell = glyph(th, topEdge)
 			default:
-				cell = boxRunes[blank]
+				cell = glyph(th, blank)
 			}
 
-			buffer.Set(rIdx, cIdx, styleGridLine.Render(cell))
+			buffer.Set(rIdx, cIdx, th.GridLine.Render(cell))
 
 		}
 	}
 }
 
-func insertEdges(puz *puzzle.Puzzle, buffer *Buffer) {
+func insertEdges(th *theme.Theme, puz *puzzle.Puzzle, buffer *Buffer) {
 	var cell *puzzle.Cell
 	var emptyC, emptyT, emptyL bool
 	for y := 0; y < puz.Height+1; y++ {
@@ -253,80 +524,176 @@ func insertEdges(puz *puzzle.Puzzle, buffer *Buffer) {
 
 			// vert lines
 			if !emptyC || !emptyL {
-				buffer.Set(y*2+1, x*2, styleGridLine.Render(boxRunes[vertLine]))
+				buffer.Set(y*2+1, x*2, th.GridLine.Render(glyph(th, vertLine)))
 			} else {
-				buffer.Set(y*2+1, x*2, boxRunes[blank])
+				buffer.Set(y*2+1, x*2, glyph(th, blank))
 			}
 
 			// horiz lines
-			if !emptyC && cell.Number() > 0 {
-				runes := make([]string, cellWidth)
-				for i := range cellWidth {
-					runes[i] = boxRunes[horizLine]
+			if !emptyC || !emptyT {
+				buffer.Set(y*2, x*2+1, th.GridLine.Render(strings.Join(horizEdgeRunes(th, cell), "")))
+			} else {
+				buffer.Set(y*2, x*2+1, th.GridLine.Render(strings.Repeat(glyph(th, blank), cellWidth)))
+			}
+		}
+	}
+}
+
+// horizEdgeRunes returns the line above a cell, including its number and any
+// top-left mark.
+func horizEdgeRunes(th *theme.Theme, cell *puzzle.Cell) []string {
+	runes := make([]string, cellWidth)
+	for i := range cellWidth {
+		runes[i] = glyph(th, horizLine)
+	}
+	if puzzle.IsCellBlankOrNil(cell) {
+		return runes
+	}
+
+	label := cell.Style.Mark
+	if cell.Number() > 0 {
+		label = strconv.Itoa(cell.Number()) + label
+	}
+	i := 0
+	for _, r := range label {
+		if i >= cellWidth {
+			break
+		}
+		runes[i] = string(r)
+		i++
+	}
+	return runes
+}
+
+// insertWordBreaks redraws the grid lines between the words of multi-word
+// answers so the enumeration is visible in the grid.
+func insertWordBreaks(th *theme.Theme, puz *puzzle.Puzzle, buffer *Buffer) {
+	for _, clues := range [][]*puzzle.Clue{puz.AcrossClues, puz.DownClues} {
+		for _, clue := range clues {
+			if clue.IsContinuation() {
+				continue
+			}
+			cells := clue.AllCells()
+			for idx, sep := range clue.WordBreaks() {
+				if idx+1 >= len(cells) {
+					continue
 				}
-				for i, nr := range strconv.Itoa(cell.Number()) {
-					runes[i] = string(nr)
+				x1, y1, _ := puz.PositionOf(cells[idx])
+				x2, y2, _ := puz.PositionOf(cells[idx+1])
+
+				switch {
+				case y1 == y2 && x2 == x1+1:
+					line := glyph(th, vertLine)
+					if sep == '-' {
+						line = "-"
+					}
+					buffer.Set(y2*2+1, x2*2, th.WordBreak.Render(line))
+				case x1 == x2 && y2 == y1+1:
+					runes := horizEdgeRunes(th, cells[idx+1])
+					if sep == '-' && runes[cellWidth/2] == glyph(th, horizLine) {
+						runes[cellWidth/2] = "-"
+					}
+					buffer.Set(y2*2, x2*2+1, th.WordBreak.Render(strings.Join(runes, "")))
 				}
-				buffer.Set(y*2, x*2+1, styleGridLine.Render(strings.Join(runes, "")))
-			} else if !emptyC || !emptyT {
-				buffer.Set(y*2, x*2+1, styleGridLine.Render(strings.Repeat(boxRunes[horizLine], cellWidth)))
+			}
+		}
+	}
+}
+
+func insertCells(th *theme.Theme, puz *puzzle.Puzzle, buffer *Buffer, selectedClue *puzzle.Clue) {
+	for y := 0; y < puz.Height; y++ {
+		for x := 0; x < puz.Width; x++ {
+			cell := puz.CellAt(x, y)
+			if !puzzle.IsCellBlankOrNil(cell) {
+				buffer.Set(y*2+1, x*2+1, renderCell(th, cell, selectedClue, false))
 			} else {
-				buffer.Set(y*2, x*2+1, styleGridLine.Render(strings.Repeat(boxRunes[blank], cellWidth)))
+				buffer.Set(y*2+1, x*2+1, strings.Repeat(glyph(th, blank), cellWidth))
 			}
 		}
 	}
 }
 
-func insertCells(puz *puzzle.Puzzle, buffer *Buffer, selectedClue *puzzle.Clue) {
+// insertCompactCells draws each cell as a single character, with no grid
+// lines, numbers or word breaks. Circled cells are underlined.
+func insertCompactCells(th *theme.Theme, puz *puzzle.Puzzle, buffer *Buffer, selectedClue *puzzle.Clue) {
 	for y := 0; y < puz.Height; y++ {
 		for x := 0; x < puz.Width; x++ {
 			cell := puz.CellAt(x, y)
 			if !puzzle.IsCellBlankOrNil(cell) {
-				text := boxRunes[empty]
-				if !cell.IsEmpty() {
-					text = string(*cell.Input)
-				}
+				buffer.Set(y, x, renderCell(th, cell, selectedClue, true))
+			} else {
+				buffer.Set(y, x, glyph(th, blank))
+			}
+		}
+	}
+}
 
-				style := lipgloss.NewStyle().Inherit(styleCellPadding)
-				isSelected := cell.IsSelected
-				isHighlighted := selectedClue == cell.ClueHoriz || selectedClue == cell.ClueVert
+// renderCell draws the cell's letter, highlighted as part of the selected
+// clue and colored once checked. Checked letters are marked without color
+// too, with a tick or a slash after them, and circled ones have a circle
+// before them. Where there is no room for the marks, in compact and for a
+// rebus, right ones are in italics, wrong ones struck through and circled
+// ones underlined instead.
+func renderCell(th *theme.Theme, cell *puzzle.Cell, selectedClue *puzzle.Clue, compact bool) string {
+	text := glyph(th, empty)
+	if !cell.IsEmpty() {
+		text = cell.Input()
+	}
+	fits := !compact && lipgloss.Width(text) == 1
 
-				if isSelected {
-					style = style.Inherit(styleHighlightCell)
-				} else if isHighlighted {
-					style = style.Inherit(styleHighlightClue)
-				}
+	style := lipgloss.NewStyle().Underline(!fits && cell.IsCircled())
+	if cell.Style.Color != "" && !th.Mono {
+		style = style.Background(lipgloss.Color(cell.Style.Color))
+	} else if cell.Style.Shaded {
+		style = style.Background(th.Colors.Shaded)
+	}
+	isSelected := cell.IsSelected
+	isHighlighted := selectedClue.SameAnswer(cell.ClueHoriz) || selectedClue.SameAnswer(cell.ClueVert)
 
-				if cell.IsEmpty() && (isHighlighted || isSelected) {
-					text = boxRunes[emptySelected]
-				}
+	if isSelected {
+		style = style.Inherit(th.HighlightCell)
+	} else if isHighlighted {
+		style = style.Inherit(th.HighlightClue)
+	}
 
-				if !cell.IsEmpty() && cell.ShowChecked {
-					if cell.IsCorrect() {
-						if isSelected {
-							style = style.Background(colorCorrect)
-						} else {
-							style = style.Foreground(colorCorrect)
-						}
-					} else {
-						if isSelected {
-							style = style.Background(colorError)
-						} else {
-							style = style.Foreground(colorError)
-						}
-					}
-				}
+	if cell.IsEmpty() && (isHighlighted || isSelected) {
+		text = glyph(th, emptySelected)
+	}
 
-				if cell.IsCircled {
-					text = fmt.Sprintf("(%s)", text)
-				}
+	isChecked := !cell.IsEmpty() && cell.ShowChecked
+	mark := glyph(th, blank)
+	if isChecked {
+		color := th.Colors.Correct
+		mark = glyph(th, checkMark)
+		if !cell.IsCorrect() {
+			color = th.Colors.Error
+			mark = glyph(th, wrongMark)
+		}
+		if isSelected {
+			style = style.Background(color)
+		} else {
+			style = style.Foreground(color)
+		}
+	}
 
-				buffer.Set(y*2+1, x*2+1, style.Render(text))
-			} else {
-				buffer.Set(y*2+1, x*2+1, strings.Repeat(boxRunes[blank], cellWidth))
-			}
+	if !fits {
+		if isChecked {
+			style = style.Italic(cell.IsCorrect()).Strikethrough(!cell.IsCorrect())
+		}
+		if compact {
+			return style.Render(text)
 		}
+		return style.Inherit(styleCellPadding).Render(text)
+	}
+	return style.Render(circleMark(th, cell) + text + mark)
+}
+
+// circleMark is the circle drawn before a circled cell's letter, or a blank.
+func circleMark(th *theme.Theme, cell *puzzle.Cell) string {
+	if cell.IsCircled() {
+		return glyph(th, circle)
 	}
+	return glyph(th, blank)
 }
 
 //   _____ _
@@ -336,113 +703,183 @@ func insertCells(puz *puzzle.Puzzle, buffer *Buffer, selectedClue *puzzle.Clue)
 // | |____| | |_| |  __\__ \
 //  \_____|_|\__,_|\___|___/
 
-func renderClues(box common.LayoutBox, puzzle *puzzle.Puzzle, selectedClue *puzzle.Clue, focus bool) string {
-	acrossTitle := styleTitle.Render("Across:")
-	acrossText, lnAcross := renderClueSet(box.W, puzzle.AcrossClues, selectedClue, focus)
-	downTitle := styleTitle.Render("Down:")
-	downText, lnDown := renderClueSet(box.W, puzzle.DownClues, selectedClue, focus)
-
-	allClues := lipgloss.JoinVertical(lipgloss.Left, acrossTitle, acrossText, downTitle, downText)
+// renderClues draws a clue panel, scrolled to the selected clue and then by
+// the view's ClueScroll.
+func renderClues(th *theme.Theme, panel cluePanel, selectedClue *puzzle.Clue, focus bool, view common.PuzzleView) (string, cluePanelHits) {
+	box := panel.box
+	var draft *string
+	if view.EditingNote {
+		draft = &view.NoteDraft
+	}
 
-	// ensure selected clue is visible
-	lnSelected := lnAcross + 1
-	if lnAcross < 0 {
-		lnSelected = lipgloss.Height(acrossText) + 2 + lnDown
+	var sections []string
+	var lines []*puzzle.Clue
+	lnSelected := -1
+	for _, set := range panel.sets {
+		if set.title != "" {
+			sections = append(sections, th.Title.Render(set.title))
+			lines = append(lines, nil)
+		}
+		text, ln, setLines := renderClueSet(th, box.W, set.clues, selectedClue, focus, draft, view.UnfilledOnly)
+		if text == "" {
+			continue
+		}
+		// ensure selected clue is visible
+		if ln >= 0 {
+			lnSelected = len(lines) + ln
+		}
+		sections = append(sections, text)
+		lines = append(lines, setLines...)
 	}
 
-	allClues = centerLine(allClues, box.H-2, lnSelected)
+	text := strings.Split(lipgloss.JoinVertical(lipgloss.Left, sections...), "\n")
+	for len(lines) < len(text) {
+		lines = append(lines, nil)
+	}
+	start, end, scroll := scrollWindow(len(text), box.H-2, lnSelected, view.ClueScroll)
+	allClues := strings.Join(text[start:end], "\n")
 
-	style := styleBorder
+	style := th.Border
 	if focus {
-		style = style.BorderForeground(colorFocusedBorder)
+		style = style.BorderForeground(th.Colors.FocusedBorder)
+	}
+	title := panel.title
+	if view.UnfilledOnly {
+		title += " (unfilled)"
 	}
-	boxedClues := style.Border(titledBorder("Clues")).Height(box.H - 2).Width(box.W - 2).Render(allClues)
+	boxedClues := style.Border(titledBorder(th, title)).Height(box.H - 2).Width(box.W - 2).Render(allClues)
 
-	return boxedClues
+	// inside the border and padding
+	hits := cluePanelHits{x: box.X + 2, y: box.Y + 1, w: box.W - 4, h: box.H - 2, lines: lines[start:end], scroll: scroll}
+	return boxedClues, hits
 }
 
-func renderClueSet(W int, clues []*puzzle.Clue, selectedClue *puzzle.Clue, focus bool) (string, int) {
+// renderClueSet lists the clues, expanding the selected one. Filled answers
+// are struck through, or left out when unfilled is set, and checked ones are
+// marked. When draft is set the note editor is shown under the selected clue.
+// The clue on each line is returned along with the selected clue's last line.
+func renderClueSet(th *theme.Theme, W int, clues []*puzzle.Clue, selectedClue *puzzle.Clue, focus bool, draft *string, unfilled bool) (string, int, []*puzzle.Clue) {
 	var out string
 	var lineNum = -1
+	var lines []*puzzle.Clue
 
-	for i, clue := range clues {
-		num := fmt.Sprintf("%2d. ", clue.Number)
-		clueText := common.WrapString(clue.Text, uint(W-4-lipgloss.Width(num)))
-		clueText = fmt.Sprintf("%s (%d)", clueText, len(clue.Cells))
-		if selectedClue == clue {
-			clueText = styleHighlightClue.Render(clueText)
+	for _, clue := range clues {
+		// continuations are shown with the first part of their answer
+		if clue.IsContinuation() {
+			continue
+		}
+		num := fmt.Sprintf("%2s. ", clue.Label())
+		if clue.Note != "" {
+			num = fmt.Sprintf("%2s%s ", clue.Label(), glyph(th, noteMark))
+		}
+		filled := clue.IsFilled()
+		if unfilled && filled && !selectedClue.SameAnswer(clue) {
+			continue
+		}
+		style := th.CellText
+		if selectedClue.SameAnswer(clue) {
+			style = th.HighlightClue
+		} else if filled {
+			style = th.FilledClue
+		}
+		clueText := renderMarkup(clue.DisplayText(), style) + style.Render(fmt.Sprintf(" (%s)", clue.EnumerationText()))
+		if clue.IsVerified() {
+			clueText += th.Verified.Render(" " + glyph(th, checkMark))
+		}
+		clueText = common.WrapString(clueText, uint(W-4-lipgloss.Width(num)))
+		if selectedClue.SameAnswer(clue) {
 			if focus {
-				focusText := renderFocusedClue(clue)
+				focusText := renderFocusedClue(th, clue)
 				clueText = lipgloss.JoinVertical(lipgloss.Left, clueText, focusText)
 			}
+			if draft != nil {
+				clueText = lipgloss.JoinVertical(lipgloss.Left, clueText, renderNoteEditor(th, W-4-lipgloss.Width(num), *draft))
+			} else if clue.Note != "" {
+				note := common.WrapString(th.Note.Render(clue.Note), uint(W-4-lipgloss.Width(num)))
+				clueText = lipgloss.JoinVertical(lipgloss.Left, clueText, note)
+			}
 			lineNum = lipgloss.Height(out) + lipgloss.Height(clueText)
-		} else {
-			clueText = styleCellText.Render(clueText)
 		}
 		fullClue := lipgloss.JoinHorizontal(lipgloss.Top, num, clueText)
-		if i == 0 {
+		for range lipgloss.Height(fullClue) {
+			lines = append(lines, clue)
+		}
+		if out == "" {
 			out = fullClue
 		} else {
 			out = lipgloss.JoinVertical(lipgloss.Left, out, fullClue)
 		}
 	}
 
-	return out, lineNum
+	return out, lineNum, lines
 }
 
-func renderFocusedClue(clue *puzzle.Clue) string {
+// renderNoteEditor draws the note popup W characters wide.
+func renderNoteEditor(th *theme.Theme, W int, draft string) string {
+	text := common.WrapString(draft+th.HighlightCell.Render(" "), uint(W-4))
+	help := th.CellText.Render("enter: Save | esc: Cancel")
+	return th.Border.Border(titledBorder(th, "Note")).BorderForeground(th.Colors.FocusedBorder).Width(W - 2).Render(lipgloss.JoinVertical(lipgloss.Left, text, help))
+}
+
+func renderFocusedClue(th *theme.Theme, clue *puzzle.Clue) string {
 	if clue == nil || len(clue.Cells) == 0 {
 		return ""
 	}
 
-	w := len(clue.Cells)*2 + 1
+	cells := clue.AllCells()
+	breaks := clue.WordBreaks()
+	w := len(cells)*2 + 1
 	buffer := NewBuffer(w, 3)
 
-	for i, cell := range clue.Cells {
-		buffer.Set(0, i*2, boxRunes[topEdge])
-		buffer.Set(1, i*2, boxRunes[vertLine])
-		buffer.Set(2, i*2, boxRunes[bottomEdge])
+	for i, cell := range cells {
+		buffer.Set(0, i*2, glyph(th, topEdge))
+		buffer.Set(1, i*2, glyph(th, vertLine))
+		buffer.Set(2, i*2, glyph(th, bottomEdge))
+		if sep, ok := breaks[i-1]; ok {
+			line := glyph(th, vertLine)
+			if sep == '-' {
+				line = "-"
+			}
+			buffer.Set(1, i*2, th.WordBreak.Render(line))
+		}
 
-		buffer.Set(0, i*2+1, strings.Repeat(boxRunes[horizLine], cellWidth))
-		cellText := boxRunes[emptySelected]
+		buffer.Set(0, i*2+1, strings.Repeat(glyph(th, horizLine), cellWidth))
+		cellText := glyph(th, emptySelected)
 		if !cell.IsEmpty() {
-			cellText = string(*cell.Input)
+			cellText = cell.Input()
+		}
+		if lipgloss.Width(cellText) == 1 {
+			cellText = circleMark(th, cell) + cellText + glyph(th, blank)
+		} else {
+			cellText = styleCellPadding.Underline(cell.IsCircled()).Render(cellText)
 		}
-		cellText = styleCellPadding.Render(cellText)
 		if cell.IsSelected {
-			cellText = styleHighlightCell.Render(cellText)
+			cellText = th.HighlightCell.Render(cellText)
 		}
 		buffer.Set(1, i*2+1, cellText)
-		buffer.Set(2, i*2+1, strings.Repeat(boxRunes[horizLine], cellWidth))
+		buffer.Set(2, i*2+1, strings.Repeat(glyph(th, horizLine), cellWidth))
 	}
 
-	buffer.Set(1, w-1, boxRunes[vertLine])
-	buffer.Set(0, w-1, boxRunes[topRight])
-	buffer.Set(2, w-1, boxRunes[bottomRight])
-	buffer.Set(0, 0, boxRunes[topLeft])
-	buffer.Set(2, 0, boxRunes[bottomLeft])
+	buffer.Set(1, w-1, glyph(th, vertLine))
+	buffer.Set(0, w-1, glyph(th, topRight))
+	buffer.Set(2, w-1, glyph(th, bottomRight))
+	buffer.Set(0, 0, glyph(th, topLeft))
+	buffer.Set(2, 0, glyph(th, bottomLeft))
 
 	return buffer.String()
 }
 
-func centerLine(str string, maxH, n int) string {
-	lines := strings.Split(str, "\n")
-	lineCount := len(lines)
-	if lineCount < maxH || maxH < 0 || n < 0 {
-		return str
-	}
-	startIdx := n - (maxH / 2)
-	if startIdx < 0 {
-		startIdx = 0
+// scrollWindow picks maxH of lineCount lines to show, centered on line n and
+// then moved by scroll. The scroll is returned kept in range.
+func scrollWindow(lineCount, maxH, n, scroll int) (int, int, int) {
+	if lineCount <= maxH || maxH < 0 {
+		return 0, lineCount, 0
 	}
+	center := max(n-(maxH/2), 0)
+	startIdx := min(max(center+scroll, 0), lineCount-maxH)
+	center = min(center, lineCount-maxH)
 
-	endIdx := startIdx + maxH
-	if endIdx > lineCount {
-		endIdx = lineCount
-		startIdx = lineCount - maxH
-	}
-
-	return strings.Join(lines[startIdx:endIdx], "\n")
+	return startIdx, startIdx + maxH, startIdx - center
 }
 
 //   _____ _        _               ____
@@ -453,10 +890,38 @@ func centerLine(str string, maxH, n int) string {
 // |_____/ \__\__,_|\__|\__,_|___/ |____/ \__,_|_|
 //
 
-func renderStatusBar(box common.LayoutBox) string {
-	shortcuts := "esc: Exit | ctrl+l: Check letter | crtl+w: Check word | ctrl+a: Check puzzle | ctrl+r: Reveal word | ctrl+p: Reveal puzzle"
+// renderVimStatusBar shows the mode, or the : command being typed, in place
+// of the shortcuts.
+func renderVimStatusBar(th *theme.Theme, box common.LayoutBox, vim common.VimState) string {
+	var status string
+	switch {
+	case vim.Mode == common.VimCommand:
+		status = ":" + vim.Command + th.HighlightCell.Render(" ")
+	case vim.Mode == common.VimInsert:
+		status = th.Title.Render("-- INSERT --")
+	default:
+		status = lipgloss.NewStyle().Foreground(th.Colors.StatusBar).Render("i: Insert | :12a: Go to clue | :w: Save | :q: Back")
+		if vim.Pending != "" || vim.Count > 0 {
+			status = strings.TrimPrefix(fmt.Sprintf("%d%s", vim.Count, vim.Pending), "0")
+		}
+	}
+	return lipgloss.NewStyle().Width(box.W).MaxWidth(box.W).Render(status)
+}
+
+func renderStatusBar(th *theme.Theme, box common.LayoutBox, keys keymap.KeyMap) string {
 	version := "Cross-cli version 0.1"
-	shortcuts = lipgloss.NewStyle().Foreground(colorStatusBar).Render(shortcuts)
+	help := keys.HelpText(keymap.Help, "")
+
+	// as many shortcuts as fit, always ending with help
+	shortcuts := ""
+	for _, text := range keys.ShortHelp(keymap.PuzzleStatusActions) {
+		next := keymap.JoinHelp(shortcuts, text)
+		if lipgloss.Width(keymap.JoinHelp(next, help))+lipgloss.Width(version)+1 > box.W {
+			break
+		}
+		shortcuts = next
+	}
+	shortcuts = lipgloss.NewStyle().Foreground(th.Colors.StatusBar).Render(keymap.JoinHelp(shortcuts, help))
 
 	scLen := lipgloss.Width(shortcuts)
 	vLen := lipgloss.Width(version)
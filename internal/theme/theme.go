@@ -0,0 +1,263 @@
+// Package theme holds the colors and styles the screens are drawn with.
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/robertcurry0216/cross/internal/config"
+)
+
+// Theme is the active set of colors and styles. Every screen draws with the
+// one in the state rather than styles of its own.
+type Theme struct {
+	Name string
+	// drawn without color, using bold, underline and reverse video
+	Mono bool
+	// drawn with ASCII rather than box drawing characters
+	ASCII bool
+	// the colors the theme was built from, before Mono drops them
+	Palette config.ColorsConfig
+	Colors  Colors
+
+	Title,
+	CellText,
+	Border,
+	GridLine,
+	WordBreak,
+	Note,
+	FilledClue,
+	Verified,
+	HighlightClue,
+	HighlightCell lipgloss.Style
+}
+
+// Colors are the theme's colors, ready for lipgloss. They are all
+// lipgloss.NoColor in a Mono theme.
+type Colors struct {
+	HighlightBG,
+	HighlightFG,
+	Error,
+	Correct,
+	StatusBar,
+	FocusedBorder,
+	WordBreak,
+	Shaded,
+	GridLine lipgloss.TerminalColor
+}
+
+// definition is a theme as it is built in or written in a theme file.
+type definition struct {
+	Colors config.ColorsConfig `toml:"colors"`
+	// hints and help at full brightness rather than faint
+	Bright bool `toml:"bright"`
+	// no colors at all
+	Monochrome bool `toml:"monochrome"`
+}
+
+var builtin = map[string]definition{
+	"default": {
+		Colors: config.ColorsConfig{
+			HighlightBG:   config.Color{Light: "8", Dark: "250"},
+			HighlightFG:   config.Color{Light: "15", Dark: "0"},
+			Error:         config.Color{Light: "9", Dark: "1"},
+			Correct:       config.Color{Light: "2", Dark: "10"},
+			StatusBar:     config.Color{Light: "4", Dark: "12"},
+			FocusedBorder: config.Color{Light: "2", Dark: "10"},
+			WordBreak:     config.Color{Light: "4", Dark: "12"},
+			Shaded:        config.Color{Light: "253", Dark: "238"},
+			GridLine:      config.Color{Light: "241", Dark: "7"},
+		},
+	},
+	"high-contrast": {
+		Colors: config.ColorsConfig{
+			HighlightBG:   config.Color{Light: "0", Dark: "15"},
+			HighlightFG:   config.Color{Light: "15", Dark: "0"},
+			Error:         config.Color{Light: "1", Dark: "9"},
+			Correct:       config.Color{Light: "2", Dark: "10"},
+			StatusBar:     config.Color{Light: "0", Dark: "15"},
+			FocusedBorder: config.Color{Light: "4", Dark: "14"},
+			WordBreak:     config.Color{Light: "5", Dark: "13"},
+			Shaded:        config.Color{Light: "250", Dark: "240"},
+			GridLine:      config.Color{Light: "0", Dark: "15"},
+		},
+		Bright: true,
+	},
+	// blue and orange for right and wrong, from the Okabe-Ito palette, which
+	// can be told apart with the common kinds of color blindness
+	"colorblind": {
+		Colors: config.ColorsConfig{
+			HighlightBG:   config.Color{Light: "8", Dark: "250"},
+			HighlightFG:   config.Color{Light: "15", Dark: "0"},
+			Error:         config.Color{Light: "#d55e00", Dark: "#e69f00"},
+			Correct:       config.Color{Light: "#0072b2", Dark: "#56b4e9"},
+			StatusBar:     config.Color{Light: "8", Dark: "250"},
+			FocusedBorder: config.Color{Light: "#0072b2", Dark: "#56b4e9"},
+			WordBreak:     config.Color{Light: "#cc79a7", Dark: "#cc79a7"},
+			Shaded:        config.Color{Light: "253", Dark: "238"},
+			GridLine:      config.Color{Light: "241", Dark: "7"},
+		},
+	},
+	"solarized": {
+		Colors: config.ColorsConfig{
+			HighlightBG:   config.Color{Light: "#268bd2", Dark: "#268bd2"},
+			HighlightFG:   config.Color{Light: "#fdf6e3", Dark: "#002b36"},
+			Error:         config.Color{Light: "#dc322f", Dark: "#dc322f"},
+			Correct:       config.Color{Light: "#859900", Dark: "#859900"},
+			StatusBar:     config.Color{Light: "#586e75", Dark: "#93a1a1"},
+			FocusedBorder: config.Color{Light: "#2aa198", Dark: "#2aa198"},
+			WordBreak:     config.Color{Light: "#cb4b16", Dark: "#cb4b16"},
+			Shaded:        config.Color{Light: "#eee8d5", Dark: "#073642"},
+			GridLine:      config.Color{Light: "#93a1a1", Dark: "#586e75"},
+		},
+	},
+	"monochrome": {
+		Monochrome: true,
+	},
+}
+
+// Names returns the built in themes, sorted.
+func Names() []string {
+	names := make([]string, 0, len(builtin))
+	for name := range builtin {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Options change how a theme is drawn, whatever its colors.
+type Options struct {
+	// hints and help at full brightness rather than faint
+	Bright bool
+	// no colors at all
+	Mono bool
+	// ASCII borders
+	ASCII bool
+}
+
+// Load returns the theme named in the config, built in or read from
+// <dir>/<name>.toml, with the config's own colors over it. It is Mono when
+// the theme asks for it, NO_COLOR is set or the terminal has no colors, and
+// ASCII when the config asks for it or the locale isn't UTF-8.
+func Load(cfg config.Config, dir string) (*Theme, error) {
+	name := cfg.Display.Theme
+	if name == "" {
+		name = "default"
+	}
+
+	def, ok := builtin[name]
+	if !ok {
+		var err error
+		def, err = readFile(filepath.Join(dir, name+".toml"))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("unknown theme %q, expected one of %s or a file in %s", name, strings.Join(Names(), ", "), dir)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	colors := builtin["default"].Colors.Merge(def.Colors).Merge(cfg.Colors)
+	ascii := cfg.Display.Charset == config.CharsetASCII || (cfg.Display.Charset == config.CharsetAuto && !UTF8Locale())
+	return New(name, colors, Options{Bright: def.Bright, Mono: def.Monochrome || NoColor(), ASCII: ascii}), nil
+}
+
+// readFile reads a theme file. It is written like the config, with the
+// colors in a [colors] table.
+func readFile(path string) (definition, error) {
+	var def definition
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return def, err
+	}
+
+	md, err := toml.Decode(string(raw), &def)
+	if err != nil {
+		return def, &config.Error{Path: path, Msg: strings.TrimPrefix(err.Error(), "toml: ")}
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return def, &config.Error{Path: path, Key: undecoded[0].String(), Msg: "unknown setting"}
+	}
+	return def, nil
+}
+
+// NoColor reports whether colors should be left out, because NO_COLOR is set
+// or the terminal doesn't have any.
+func NoColor() bool {
+	return os.Getenv("NO_COLOR") != "" || lipgloss.ColorProfile() == termenv.Ascii
+}
+
+// UTF8Locale reports whether the locale, from the first of LC_ALL, LC_CTYPE
+// and LANG that is set, is UTF-8. With none of them set it is taken to be.
+func UTF8Locale() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			locale = strings.ToLower(locale)
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+	return true
+}
+
+// asciiBorder is lipgloss.RoundedBorder in ASCII.
+var asciiBorder = lipgloss.Border{
+	Top:         "-",
+	Bottom:      "-",
+	Left:        "|",
+	Right:       "|",
+	TopLeft:     "+",
+	TopRight:    "+",
+	BottomLeft:  "+",
+	BottomRight: "+",
+}
+
+// New builds a theme from its colors and options.
+func New(name string, colors config.ColorsConfig, opts Options) *Theme {
+	t := &Theme{Name: name, Mono: opts.Mono, ASCII: opts.ASCII, Palette: colors}
+	if opts.Mono {
+		none := lipgloss.NoColor{}
+		t.Colors = Colors{none, none, none, none, none, none, none, none, none}
+	} else {
+		t.Colors = Colors{
+			HighlightBG:   colors.HighlightBG.Adaptive(),
+			HighlightFG:   colors.HighlightFG.Adaptive(),
+			Error:         colors.Error.Adaptive(),
+			Correct:       colors.Correct.Adaptive(),
+			StatusBar:     colors.StatusBar.Adaptive(),
+			FocusedBorder: colors.FocusedBorder.Adaptive(),
+			WordBreak:     colors.WordBreak.Adaptive(),
+			Shaded:        colors.Shaded.Adaptive(),
+			GridLine:      colors.GridLine.Adaptive(),
+		}
+	}
+
+	border := lipgloss.RoundedBorder()
+	if opts.ASCII {
+		border = asciiBorder
+	}
+	t.Border = lipgloss.NewStyle().Border(border).Padding(0, 1)
+	t.GridLine = lipgloss.NewStyle().Foreground(t.Colors.GridLine)
+	t.WordBreak = lipgloss.NewStyle().Foreground(t.Colors.WordBreak).Bold(true)
+	t.Title = lipgloss.NewStyle().Bold(true)
+	t.CellText = lipgloss.NewStyle().Faint(!opts.Bright)
+	t.Note = lipgloss.NewStyle().Italic(true).Foreground(t.Colors.StatusBar)
+	t.FilledClue = lipgloss.NewStyle().Faint(!opts.Bright).Strikethrough(true)
+	t.Verified = lipgloss.NewStyle().Foreground(t.Colors.Correct)
+	t.HighlightClue = lipgloss.NewStyle().Faint(false).Bold(true)
+	t.HighlightCell = lipgloss.NewStyle().Faint(false).Bold(true).Background(t.Colors.HighlightBG).Foreground(t.Colors.HighlightFG)
+
+	// without colors the highlights need something else to stand out
+	if opts.Mono {
+		t.HighlightClue = t.HighlightClue.Underline(true)
+		t.HighlightCell = t.HighlightCell.Reverse(true)
+		t.Verified = t.Verified.Bold(true)
+	}
+	return t
+}
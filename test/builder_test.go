@@ -2,6 +2,7 @@ package puzzle_test
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/robertcurry0216/cross/internal/puzzle"
@@ -53,11 +54,11 @@ func TestInitPuzzle(t *testing.T) {
 				t.Errorf("Expected blank cell at index 3, got non-blank")
 			}
 		} else {
-			if cell.Solution != puz.Solution[i] {
-				t.Errorf("Expected solution %c at index %d, got %c", puz.Solution[i], i, cell.Solution)
+			if cell.Solution != string(puz.Solution[i]) {
+				t.Errorf("Expected solution %c at index %d, got %s", puz.Solution[i], i, cell.Solution)
 			}
-			if cell.Input != &puz.Input[i] {
-				t.Errorf("Input pointer not set correctly at index %d", i)
+			if cell.SetInput("x"); puz.Input[i] != 'X' {
+				t.Errorf("Input not written back to the grid at index %d", i)
 			}
 		}
 	}
@@ -221,3 +222,77 @@ func TestPuzBuilderChecksumRegion(t *testing.T) {
 		t.Error("checksumRegion should produce different results for different inputs")
 	}
 }
+
+func TestLinkClues(t *testing.T) {
+	// A B C
+	// D E F
+	// G H I
+	puz := puzzle.NewPuzzle()
+	puz.Width = 3
+	puz.Height = 3
+	puz.Solution = []byte("ABCDEFGHI")
+	puz.Input = make([]byte, 9)
+	puz.Clues = []*puzzle.Clue{
+		puzzle.NewClue("1-Across/5-Across Famous saying"), // 1 across
+		puzzle.NewClue("Down 1"),                          // 1 down
+		puzzle.NewClue("See 3-Down"),                      // 2 down
+		puzzle.NewClue("With 2-Down, a pair"),             // 3 down
+		puzzle.NewClue("Across 4"),                        // 4 across
+		puzzle.NewClue("See 1-Across"),                    // 5 across
+	}
+
+	if err := puzzle.InitPuzzle(puz); err != nil {
+		t.Fatalf("InitPuzzle failed: %v", err)
+	}
+
+	head := puz.FindClue(1, false)
+	tail := puz.FindClue(5, false)
+	if len(head.Parts) != 2 || head.Parts[0] != head || head.Parts[1] != tail {
+		t.Fatalf("Expected 1-Across to be linked to 5-Across, got %v parts", len(head.Parts))
+	}
+	if !tail.IsContinuation() || head.IsContinuation() {
+		t.Errorf("Expected only 5-Across to be a continuation")
+	}
+	if tail.Root() != head {
+		t.Errorf("Expected 5-Across root to be 1-Across")
+	}
+	if len(head.AllCells()) != 6 {
+		t.Errorf("Expected 6 cells in the combined answer, got %d", len(head.AllCells()))
+	}
+	if head.NextPart() != tail || tail.PrevPart() != head || tail.NextPart() != nil {
+		t.Errorf("Part navigation is not in answer order")
+	}
+	if head.Label() != "1/5" {
+		t.Errorf("Expected label 1/5, got %s", head.Label())
+	}
+
+	down := puz.FindClue(3, true)
+	if down.Root() != down || puz.FindClue(2, true).Root() != down {
+		t.Errorf("Expected 2-Down to continue 3-Down")
+	}
+
+	if puz.FindClue(4, false).Parts != nil {
+		t.Errorf("Expected 4-Across to be standalone")
+	}
+}
+
+func TestBuildCellStyles(t *testing.T) {
+	builder, err := puzzle.NewBuilderFromFile(filepath.Join("testdata", "test.puz"))
+	if err != nil {
+		t.Fatalf("Failed to create builder: %v", err)
+	}
+	puz, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Failed to build puzzle: %v", err)
+	}
+
+	for i, cell := range puz.Grid {
+		expected := i == 0 || i == 6 || i == 12
+		if cell.IsCircled() != expected {
+			t.Errorf("Expected circled=%v at index %d", expected, i)
+		}
+		if cell.Style.Shaded || cell.Style.Color != "" {
+			t.Errorf("Expected no shading at index %d", i)
+		}
+	}
+}
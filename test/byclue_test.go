@@ -0,0 +1,70 @@
+package puzzle_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestByClueTyping(t *testing.T) {
+	// 1 Across, four letters
+	m := openPuzzle(t, puzzleOptions{})
+	m = update(m, keys("ctrl+b", "C", "R", "A", "B")...)
+	if view := m.State().PuzzleView; view.X != 3 || view.Y != 0 {
+		t.Errorf("Expected the cursor to stay on the last letter, got (%d, %d)", view.X, view.Y)
+	}
+
+	// typing past the end keeps to the answer
+	m = update(m, keys("X")...)
+	if got := inputAt(m, 3, 0); got != "X" {
+		t.Errorf("Expected the last letter replaced, got %q", got)
+	}
+	if view := m.State().PuzzleView; view.X != 3 || view.Y != 0 || inputAt(m, 0, 1) != "" {
+		t.Errorf("Expected nothing typed past the answer, got (%d, %d)", view.X, view.Y)
+	}
+
+	// and so does moving back from its start
+	m = update(m, keys("left", "left", "left", "left")...)
+	if view := m.State().PuzzleView; view.X != 0 || view.Y != 0 {
+		t.Errorf("Expected the cursor to stop at the first letter, got (%d, %d)", view.X, view.Y)
+	}
+}
+
+func TestByClueSkipsSolved(t *testing.T) {
+	// 5 Across is filled in, and 9 Across is the last
+	m := openPuzzle(t, puzzleOptions{filled: fillRow(map[[2]int]string{}, 0, 1, "HOSEA")})
+	m = update(m, keys("ctrl+b", "tab")...)
+	if view := m.State().PuzzleView; view.X != 0 || view.Y != 2 || view.IsVert {
+		t.Errorf("Expected tab to pass 5 Across for 7 Across, got (%d, %d) vert=%v", view.X, view.Y, view.IsVert)
+	}
+	m = update(m, keys("shift+tab")...)
+	if view := m.State().PuzzleView; view.X != 0 || view.Y != 0 {
+		t.Errorf("Expected shift+tab to pass it back to 1 Across, got (%d, %d)", view.X, view.Y)
+	}
+	m = update(m, keys("shift+tab")...)
+	if view := m.State().PuzzleView; view.X != 1 || view.Y != 4 {
+		t.Errorf("Expected shift+tab to wrap round to 9 Across, got (%d, %d)", view.X, view.Y)
+	}
+}
+
+func TestByClueSmallWidths(t *testing.T) {
+	for w := 1; w <= 30; w++ {
+		m := openPuzzle(t, puzzleOptions{width: w, height: 30})
+		view := update(m, keys("ctrl+b")...).View()
+		if !strings.Contains(view, "╭─") {
+			t.Errorf("width %d: expected the clue's box in\n%s", w, view)
+		}
+
+		// too narrow for the box's border and padding, it can't fit
+		if w < 6 {
+			continue
+		}
+		for _, line := range strings.Split(view, "\n") {
+			if ansi.StringWidth(line) > w {
+				t.Errorf("width %d: expected the clue to fit, got %q", w, line)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package puzzle_test
+
+import (
+	"testing"
+
+	"github.com/robertcurry0216/cross/internal/puzzle"
+)
+
+func TestCellUnicodeInput(t *testing.T) {
+	// Ñ 7
+	// É .
+	puz := puzzle.NewPuzzle()
+	puz.Width = 2
+	puz.Height = 2
+	puz.Solution = []byte{0xD1, '7', 0xC9, '.'}
+	puz.Input = []byte{'-', '-', 0xC9, '-'}
+	if err := puzzle.InitPuzzle(puz); err != nil {
+		t.Fatalf("InitPuzzle failed: %v", err)
+	}
+
+	enye := puz.CellAt(0, 0)
+	if enye.Solution != "Ñ" {
+		t.Errorf("Expected solution Ñ, got %q", enye.Solution)
+	}
+	if !enye.IsEmpty() || enye.Input() != "" {
+		t.Errorf("Expected '-' to load as an empty cell, got %q", enye.Input())
+	}
+
+	// inputs are upper-cased and compared without regard to case
+	enye.SetInput("ñ")
+	if enye.Input() != "Ñ" || !enye.IsCorrect() {
+		t.Errorf("Expected ñ to be entered as a correct Ñ, got %q", enye.Input())
+	}
+	if puz.Input[0] != 0xD1 {
+		t.Errorf("Expected Ñ to be stored as Latin-1 0xD1, got %#x", puz.Input[0])
+	}
+
+	enye.Clear()
+	if !enye.IsEmpty() || puz.Input[0] != '-' {
+		t.Errorf("Expected cleared cell to be stored as '-', got %#x", puz.Input[0])
+	}
+
+	if e := puz.CellAt(0, 1); e.Input() != "É" || !e.IsCorrect() {
+		t.Errorf("Expected saved É to load as a correct entry, got %q", e.Input())
+	}
+
+	testCases := []struct {
+		r        rune
+		expected bool
+	}{
+		{'a', true},
+		{'Z', true},
+		{'7', true},
+		{'ñ', true},
+		{'é', true},
+		{'8', false},
+		{'€', false},
+		{'\t', false},
+	}
+	for _, tc := range testCases {
+		if allowed := puz.AllowsInput(tc.r); allowed != tc.expected {
+			t.Errorf("AllowsInput(%q) = %v, expected %v", tc.r, allowed, tc.expected)
+		}
+	}
+}
+
+func TestCellLatin1Upper(t *testing.T) {
+	// ÿ and µ upper-case to letters outside Latin-1, so stay as they are
+	puz := puzzle.NewPuzzle()
+	puz.Width = 2
+	puz.Height = 1
+	puz.Solution = []byte{0xFF, 0xB5}
+	puz.Input = []byte{'-', '-'}
+	if err := puzzle.InitPuzzle(puz); err != nil {
+		t.Fatalf("InitPuzzle failed: %v", err)
+	}
+
+	for i, letter := range []string{"ÿ", "µ"} {
+		cell := puz.CellAt(i, 0)
+		if !puz.AllowsInput([]rune(letter)[0]) {
+			t.Errorf("Expected %s to be allowed", letter)
+		}
+		cell.SetInput(letter)
+		if cell.Input() != letter || !cell.IsCorrect() {
+			t.Errorf("Expected %s to be entered as it is, got %q", letter, cell.Input())
+		}
+		if puz.Input[i] != puz.Solution[i] {
+			t.Errorf("Expected %s to be stored as Latin-1 %#x, got %#x", letter, puz.Solution[i], puz.Input[i])
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package puzzle_test
+
+import (
+	"testing"
+
+	"github.com/robertcurry0216/cross/internal/puzzle"
+)
+
+func TestClueEnumeration(t *testing.T) {
+	testCases := []struct {
+		text        string
+		cells       int
+		enumeration string
+		display     string
+	}{
+		{"Sea creature (4)", 4, "4", "Sea creature"},
+		{"Famous saying (3,4)", 7, "3,4", "Famous saying"},
+		{"Kind of ball (5-3)", 8, "5-3", "Kind of ball"},
+		{"Spaced out (2 3)", 5, "2 3", "Spaced out"},
+		{"No enumeration", 5, "5", "No enumeration"},
+		{"Brackets (not one)", 3, "3", "Brackets (not one)"},
+		// a number in brackets that doesn't fit the answer is part of the clue
+		{"Battle year (1066)", 8, "8", "Battle year (1066)"},
+		{"Sea creature (5)", 4, "4", "Sea creature (5)"},
+	}
+
+	for _, tc := range testCases {
+		clue := puzzle.NewClue(tc.text)
+		for range tc.cells {
+			clue.Cells = append(clue.Cells, puzzle.NewCell())
+		}
+		if clue.EnumerationText() != tc.enumeration {
+			t.Errorf("NewClue(%q).EnumerationText() = %q, expected %q", tc.text, clue.EnumerationText(), tc.enumeration)
+		}
+		if clue.DisplayText() != tc.display {
+			t.Errorf("NewClue(%q).DisplayText() = %q, expected %q", tc.text, clue.DisplayText(), tc.display)
+		}
+	}
+}
+
+func TestClueWordBreaks(t *testing.T) {
+	clue := puzzle.NewClue("Kind of ball (2,3-2)")
+	for range 7 {
+		clue.Cells = append(clue.Cells, puzzle.NewCell())
+	}
+
+	breaks := clue.WordBreaks()
+	if len(breaks) != 2 || breaks[1] != ',' || breaks[4] != '-' {
+		t.Errorf("Expected breaks after cells 1 and 4, got %v", breaks)
+	}
+
+	// enumerations that don't match the answer length are ignored
+	clue.Cells = clue.Cells[:5]
+	if breaks := clue.WordBreaks(); len(breaks) != 0 {
+		t.Errorf("Expected no breaks for a mismatched enumeration, got %v", breaks)
+	}
+
+	// fall back to the cell count when there is no enumeration
+	clue = puzzle.NewClue("Plain")
+	clue.Cells = append(clue.Cells, puzzle.NewCell(), puzzle.NewCell())
+	if clue.EnumerationText() != "2" {
+		t.Errorf("Expected enumeration 2, got %q", clue.EnumerationText())
+	}
+}
+
+func TestClueFilledAndVerified(t *testing.T) {
+	clue := puzzle.NewClue("Sea creature (4)")
+	for _, letter := range []string{"C", "R", "A", "B"} {
+		cell := puzzle.NewCell()
+		cell.Solution = letter
+		clue.Cells = append(clue.Cells, cell)
+	}
+
+	if clue.IsFilled() || clue.IsVerified() {
+		t.Error("Expected an empty answer to be neither filled nor verified")
+	}
+
+	for _, cell := range clue.Cells {
+		cell.SetInput(cell.Solution)
+	}
+	if !clue.IsFilled() || clue.IsVerified() {
+		t.Error("Expected a filled answer that isn't checked to be unverified")
+	}
+
+	for _, cell := range clue.Cells {
+		cell.ShowChecked = true
+	}
+	if !clue.IsVerified() {
+		t.Error("Expected a checked, correct answer to be verified")
+	}
+
+	clue.Cells[2].SetInput("E")
+	if clue.IsVerified() {
+		t.Error("Expected a wrong letter to unverify the answer")
+	}
+}
@@ -0,0 +1,181 @@
+package puzzle_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/robertcurry0216/cross/internal/config"
+)
+
+func TestLoadConfigDefaults(t *testing.T) {
+	cfg, err := config.Load(filepath.Join(t.TempDir(), "missing.toml"))
+	if err != nil {
+		t.Fatalf("Expected a missing config to be ignored, got %v", err)
+	}
+	if !cfg.General.Autosave || len(cfg.Library.Dirs) != 1 {
+		t.Errorf("Expected the default config, got %+v", cfg)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	cfg := config.Default()
+	err := config.Parse(&cfg, `
+[general]
+autosave = false
+
+[library]
+dirs = ["/srv/puzzles", "~/crosswords"]
+
+[colors]
+error = "#ff0000"
+grid_line = { light = "240", dark = "250" }
+`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	home, _ := os.UserHomeDir()
+	if cfg.General.Autosave {
+		t.Errorf("Expected autosave to be off")
+	}
+	if len(cfg.Library.Dirs) != 2 || cfg.Library.Dirs[1] != filepath.Join(home, "crosswords") {
+		t.Errorf("Unexpected library dirs %v", cfg.Library.Dirs)
+	}
+	if cfg.Colors.Error.Light != "#ff0000" || cfg.Colors.Error.Dark != "#ff0000" {
+		t.Errorf("Expected a single color to set both variants, got %+v", cfg.Colors.Error)
+	}
+	if cfg.Colors.GridLine.Light != "240" || cfg.Colors.GridLine.Dark != "250" {
+		t.Errorf("Unexpected grid line color %+v", cfg.Colors.GridLine)
+	}
+	if cfg.Colors.Correct != config.Default().Colors.Correct {
+		t.Errorf("Expected unset colors to keep their defaults")
+	}
+}
+
+func TestParseConfigPartialColor(t *testing.T) {
+	cfg := config.Default()
+	err := config.Parse(&cfg, "[colors]\nerror = { dark = \"1\" }\ncorrect = { light = \"#00aa00\" }\n")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if cfg.Colors.Error != (config.Color{Dark: "1"}) || cfg.Colors.Correct != (config.Color{Light: "#00aa00"}) {
+		t.Errorf("Expected only the given variants set, got %+v and %+v", cfg.Colors.Error, cfg.Colors.Correct)
+	}
+
+	// the other variant is the same, unless something underneath has one
+	if adaptive := cfg.Colors.Error.Adaptive(); adaptive.Light != "1" || adaptive.Dark != "1" {
+		t.Errorf("Expected a lone dark color for both backgrounds, got %+v", adaptive)
+	}
+	base := config.ColorsConfig{Error: config.Color{Light: "9", Dark: "3"}}
+	if merged := base.Merge(cfg.Colors); merged.Error != (config.Color{Light: "9", Dark: "1"}) || merged.Correct.Dark != "" {
+		t.Errorf("Expected the light variant kept from under it, got %+v and %+v", merged.Error, merged.Correct)
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+		line int
+		key  string
+	}{
+		{"syntax", "[general]\nautosave = tru\n", 2, ""},
+		{"type", "[general]\n\nautosave = \"yes\"\n", 3, "general.autosave"},
+		{"color", "[colors]\nerror = \"purple\"\n", 2, "colors.error"},
+		{"light color", "[colors]\nerror = { light = \"300\" }\n", 2, "colors.error"},
+		{"empty color", "[colors]\nerror = {}\n", 2, "colors.error"},
+		{"unknown table", "[general]\nautosave = true\n\n[colours]\nerror = \"1\"\n", 4, "colours"},
+		{"unknown key", "[general]\nautosave = true\nsave = true\n", 3, "general.save"},
+	}
+
+	for _, tc := range testCases {
+		cfg := config.Default()
+		err := config.Parse(&cfg, tc.data)
+
+		var configErr *config.Error
+		if !errors.As(err, &configErr) {
+			t.Errorf("%s: expected a config error, got %v", tc.name, err)
+			continue
+		}
+		if configErr.Line != tc.line {
+			t.Errorf("%s: expected the error on line %d, got %d (%v)", tc.name, tc.line, configErr.Line, err)
+		}
+		if tc.key != "" && configErr.Key != tc.key {
+			t.Errorf("%s: expected key %s, got %s", tc.name, tc.key, configErr.Key)
+		}
+	}
+}
+
+func TestParseConfigInputMode(t *testing.T) {
+	cfg := config.Default()
+	if err := config.Parse(&cfg, "[general]\ninput = \"vim\"\n"); err != nil || cfg.General.Input != config.InputVim {
+		t.Errorf("Expected vim input, got %v (%v)", cfg.General.Input, err)
+	}
+
+	err := config.Parse(&cfg, "[general]\ninput = \"emacs\"\n")
+	var configErr *config.Error
+	if !errors.As(err, &configErr) || configErr.Line != 2 {
+		t.Errorf("Expected an error on line 2, got %v", err)
+	}
+}
+
+func TestParseConfigTyping(t *testing.T) {
+	cfg := config.Default()
+	if cfg.Typing != (config.TypingConfig{}) {
+		t.Errorf("Expected the typing options off by default, got %+v", cfg.Typing)
+	}
+
+	err := config.Parse(&cfg, "[typing]\nskip_filled = true\narrow_changes_direction = true\n")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !cfg.Typing.SkipFilled || !cfg.Typing.ArrowChangesDirection || cfg.Typing.JumpToNextClue {
+		t.Errorf("Expected only the given options to change, got %+v", cfg.Typing)
+	}
+}
+
+func TestParseConfigDisplay(t *testing.T) {
+	cfg := config.Default()
+	if cfg.Display.Grid != config.GridAuto || cfg.Display.Layout != config.LayoutAuto || cfg.Display.Charset != config.CharsetAuto || !cfg.Display.ClueBar {
+		t.Errorf("Expected everything picked by size and locale by default, got %+v", cfg.Display)
+	}
+
+	grid := func(d config.DisplayConfig) string { return string(d.Grid) }
+	layout := func(d config.DisplayConfig) string { return string(d.Layout) }
+	charset := func(d config.DisplayConfig) string { return string(d.Charset) }
+	testCases := []struct {
+		input string
+		// reads the setting back, nil when the value should fail on line 2
+		get  func(config.DisplayConfig) string
+		want string
+	}{
+		{"grid = \"compact\"", grid, "compact"},
+		{"grid = \"full\"", grid, "full"},
+		{"grid = \"tiny\"", nil, ""},
+		{"layout = \"zen\"", layout, "zen"},
+		{"layout = \"columns\"", layout, "columns"},
+		{"layout = \"grid\"", nil, ""},
+		{"charset = \"ascii\"", charset, "ascii"},
+		{"charset = \"unicode\"", charset, "unicode"},
+		{"charset = \"latin1\"", nil, ""},
+	}
+
+	for _, tc := range testCases {
+		cfg := config.Default()
+		err := config.Parse(&cfg, "[display]\n"+tc.input+"\n")
+		if tc.get == nil {
+			var configErr *config.Error
+			if !errors.As(err, &configErr) || configErr.Line != 2 {
+				t.Errorf("%s: expected an error on line 2, got %v", tc.input, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: Parse failed: %v", tc.input, err)
+		} else if got := tc.get(cfg.Display); got != tc.want {
+			t.Errorf("%s: expected %q, got %q", tc.input, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package puzzle_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/robertcurry0216/cross/internal/puzzle"
+)
+
+func TestTextEncodingRoundTrip(t *testing.T) {
+	raw := []byte{'C', 'a', 'f', 0xE9, ' ', 0xA9}
+	str := puzzle.EncodingLatin1.Decode(raw)
+	if str != "Café ©" {
+		t.Errorf("Expected 'Café ©', got %q", str)
+	}
+	if encoded := puzzle.EncodingLatin1.Encode(str); !bytes.Equal(encoded, raw) {
+		t.Errorf("Expected Latin-1 round trip to give %v, got %v", raw, encoded)
+	}
+
+	if encoded := puzzle.EncodingLatin1.Encode("✓"); !bytes.Equal(encoded, []byte("?")) {
+		t.Errorf("Expected unrepresentable runes to encode as '?', got %v", encoded)
+	}
+
+	utf := "Über ✓"
+	if decoded := puzzle.EncodingUTF8.Decode([]byte(utf)); decoded != utf {
+		t.Errorf("Expected %q, got %q", utf, decoded)
+	}
+
+	testCases := []struct {
+		version  string
+		expected puzzle.TextEncoding
+	}{
+		{"1.2", puzzle.EncodingLatin1},
+		{"1.3", puzzle.EncodingLatin1},
+		{"2.0", puzzle.EncodingUTF8},
+		{"", puzzle.EncodingLatin1},
+	}
+	for _, tc := range testCases {
+		if enc := puzzle.EncodingForVersion(tc.version); enc != tc.expected {
+			t.Errorf("EncodingForVersion(%q) = %v, expected %v", tc.version, enc, tc.expected)
+		}
+	}
+}
+
+func TestBuildEncodedStrings(t *testing.T) {
+	testCases := []struct {
+		file   string
+		title  string
+		clue   string
+		notes  string
+		author string
+	}{
+		{"test.puz", "Café Crossword", "Señor's 'assent'?", "Read the circled letters.", "Zoë"},
+		{"utf8.puz", "Über Puzzle ✓", "Señor's ‘assent’?", "naïve", "Zoë"},
+	}
+
+	for _, tc := range testCases {
+		tempDir := t.TempDir()
+		data, err := os.ReadFile(filepath.Join("testdata", tc.file))
+		if err != nil {
+			t.Fatalf("Failed to read test puzzle file: %v", err)
+		}
+		path := filepath.Join(tempDir, tc.file)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("Failed to write temp puzzle file: %v", err)
+		}
+
+		builder, err := puzzle.NewBuilderFromFile(path)
+		if err != nil {
+			t.Fatalf("Failed to create builder: %v", err)
+		}
+		puz, err := builder.Build()
+		if err != nil {
+			t.Fatalf("Failed to build %s: %v", tc.file, err)
+		}
+
+		if puz.Title != tc.title {
+			t.Errorf("%s: expected title %q, got %q", tc.file, tc.title, puz.Title)
+		}
+		if puz.Author != tc.author {
+			t.Errorf("%s: expected author %q, got %q", tc.file, tc.author, puz.Author)
+		}
+		if puz.Notes != tc.notes {
+			t.Errorf("%s: expected notes %q, got %q", tc.file, tc.notes, puz.Notes)
+		}
+		if puz.Clues[7].Text != tc.clue {
+			t.Errorf("%s: expected clue %q, got %q", tc.file, tc.clue, puz.Clues[7].Text)
+		}
+
+		// checksums are computed from the re-encoded strings
+		if err := builder.Validate(); err != nil {
+			t.Errorf("%s: validation failed: %v", tc.file, err)
+		}
+
+		puz.Save()
+		builder2, _ := puzzle.NewBuilderFromFile(path)
+		if _, err := builder2.Build(); err != nil {
+			t.Fatalf("%s: failed to rebuild after saving: %v", tc.file, err)
+		}
+		if err := builder2.Validate(); err != nil {
+			t.Errorf("%s: validation failed after saving: %v", tc.file, err)
+		}
+	}
+}
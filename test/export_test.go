@@ -0,0 +1,90 @@
+package puzzle_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/robertcurry0216/cross/common"
+	"github.com/robertcurry0216/cross/internal/puzzle"
+)
+
+func TestPuzzleText(t *testing.T) {
+	puz := buildTestPuzzle(t)
+	for _, cell := range puz.AcrossClues[0].Cells {
+		cell.SetInput(cell.Solution)
+	}
+
+	text := puzzle.PuzzleText(puz)
+	lines := strings.Split(text, "\n")
+	if lines[0] != "Café Crossword" || lines[1] != "by Zoë" {
+		t.Errorf("Unexpected heading %q", lines[:2])
+	}
+	if lines[3] != "C R A B #" || lines[4] != "_ _ _ _ _" || lines[7] != "# _ _ _ _" {
+		t.Errorf("Unexpected grid:\n%s", strings.Join(lines[3:8], "\n"))
+	}
+	if !strings.Contains(text, "\nAcross\n1. Shellfish (4)\n") || !strings.Contains(text, "\nDown\n1. Burn (4)\n") {
+		t.Errorf("Expected the clue lists, got:\n%s", text)
+	}
+}
+
+func TestExportText(t *testing.T) {
+	dir := t.TempDir()
+	copyFixture(t, "test.puz", filepath.Join(dir, "sunday.puz"))
+	puz, err := puzzle.LoadFromFile(filepath.Join(dir, "sunday.puz"))
+	if err != nil {
+		t.Fatalf("Failed to load puzzle: %v", err)
+	}
+
+	path, err := puzzle.ExportText(puz)
+	if err != nil {
+		t.Fatalf("ExportText failed: %v", err)
+	}
+	if path != filepath.Join(dir, "sunday.txt") {
+		t.Errorf("Expected the export next to the puzzle, got %s", path)
+	}
+	if raw, _ := os.ReadFile(path); string(raw) != puzzle.PuzzleText(puz) {
+		t.Errorf("Exported file doesn't match the puzzle text")
+	}
+
+	// exporting again leaves the first file alone
+	if err := os.WriteFile(filepath.Join(dir, "sunday-1.txt"), []byte("notes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	path, err = puzzle.ExportText(puz)
+	if err != nil {
+		t.Fatalf("ExportText failed: %v", err)
+	}
+	if path != filepath.Join(dir, "sunday-2.txt") {
+		t.Errorf("Expected the export to take the next free name, got %s", path)
+	}
+	if raw, _ := os.ReadFile(filepath.Join(dir, "sunday-1.txt")); string(raw) != "notes" {
+		t.Errorf("Expected the existing file kept, got %q", raw)
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	testCases := []struct {
+		query, text string
+		match       bool
+	}{
+		{"", "Check word", true},
+		{"chw", "Check word", true},
+		{"check wor", "Check word", true},
+		{"cw ", "Check word", true},
+		{"wc", "Check word", false},
+		{"reveal", "Check word", false},
+	}
+	for _, tc := range testCases {
+		if _, ok := common.FuzzyMatch(tc.query, tc.text); ok != tc.match {
+			t.Errorf("FuzzyMatch(%q, %q) = %v, expected %v", tc.query, tc.text, ok, tc.match)
+		}
+	}
+
+	word, _ := common.FuzzyMatch("cw", "Check word")
+	scattered, _ := common.FuzzyMatch("cw", "Clear puzzle now")
+	if word <= scattered {
+		t.Errorf("Expected word starts to score higher, got %d <= %d", word, scattered)
+	}
+}
@@ -0,0 +1,115 @@
+package puzzle_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/robertcurry0216/cross/internal/config"
+	"github.com/robertcurry0216/cross/internal/model"
+	"github.com/robertcurry0216/cross/internal/puzzle"
+	"github.com/robertcurry0216/cross/internal/theme"
+)
+
+// The tests that drive the model open a copy of test.puz, whose grid is
+//
+//	CRAB.
+//	HOSEA
+//	ALOUD
+//	ROUSE
+//	.SNOB
+//
+// with 1, 5, 7, 8 and 9 Across and 1, 2, 3, 4 and 6 Down.
+
+// puzzleOptions change how openPuzzle opens test.puz. Left out, it opens
+// with the default config and theme, autosave off, in a 100 by 40 window.
+type puzzleOptions struct {
+	config func(*config.Config)
+	theme  theme.Options
+	colors config.ColorsConfig
+	// show the notes as on first open, rather than skipping them
+	notes bool
+
+	width, height int
+
+	// letters filled in by x, y, and the cell the cursor starts on, going
+	// across
+	filled map[[2]int]string
+	x, y   int
+}
+
+// openPuzzle opens a copy of test.puz, with progress kept in a temporary
+// directory.
+func openPuzzle(t *testing.T, opts puzzleOptions) model.Model {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), "test.puz")
+	copyFixture(t, "test.puz", path)
+
+	puz, err := puzzle.LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load puzzle: %v", err)
+	}
+	// skip the notes shown on first open
+	puz.Progress.NotesSeen = !opts.notes
+
+	cfg := config.Default()
+	cfg.General.Autosave = false
+	if opts.config != nil {
+		opts.config(&cfg)
+	}
+	if opts.width == 0 {
+		opts.width, opts.height = 100, 40
+	}
+
+	m := model.NewModel(cfg, theme.New("default", opts.colors, opts.theme))
+	model.OpenPuzzle(&m, puz)
+	for pos, letter := range opts.filled {
+		puz.CellAt(pos[0], pos[1]).SetInput(letter)
+	}
+	model.SelectCell(&m, puz.CellAt(opts.x, opts.y), false)
+	return update(m, tea.WindowSizeMsg{Width: opts.width, Height: opts.height})
+}
+
+// update sends the messages to the model in turn.
+func update(m model.Model, msgs ...tea.Msg) model.Model {
+	for _, msg := range msgs {
+		next, _ := m.Update(msg)
+		m = next.(model.Model)
+	}
+	return m
+}
+
+var keyTypes = map[string]tea.KeyType{
+	"esc":       tea.KeyEsc,
+	"enter":     tea.KeyEnter,
+	"tab":       tea.KeyTab,
+	"shift+tab": tea.KeyShiftTab,
+	"backspace": tea.KeyBackspace,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"ctrl+b":    tea.KeyCtrlB,
+	"ctrl+e":    tea.KeyCtrlE,
+	"ctrl+g":    tea.KeyCtrlG,
+	"ctrl+k":    tea.KeyCtrlK,
+	"ctrl+w":    tea.KeyCtrlW,
+}
+
+// keys turns key names, like "esc", and runes into key messages.
+func keys(names ...string) []tea.Msg {
+	msgs := make([]tea.Msg, 0, len(names))
+	for _, name := range names {
+		if keyType, ok := keyTypes[name]; ok {
+			msgs = append(msgs, tea.KeyMsg{Type: keyType})
+		} else {
+			msgs = append(msgs, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name)})
+		}
+	}
+	return msgs
+}
+
+func inputAt(m model.Model, x, y int) string {
+	return m.State().Puzzle.CellAt(x, y).Input()
+}
@@ -0,0 +1,83 @@
+package puzzle_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/robertcurry0216/cross/internal/model"
+	"github.com/robertcurry0216/cross/internal/puzzle"
+	"github.com/robertcurry0216/cross/internal/screen"
+)
+
+// showsInfo reports whether the info screen is the one on top.
+func showsInfo(m model.Model) bool {
+	views := m.State().Views
+	_, ok := views[len(views)-1].(*screen.InfoScreen)
+	return ok
+}
+
+func TestNotesOnFirstOpen(t *testing.T) {
+	m := openPuzzle(t, puzzleOptions{notes: true})
+	if !showsInfo(m) || !strings.Contains(m.View(), "Read the circled letters.") {
+		t.Errorf("Expected the notes on first open, got\n%s", m.View())
+	}
+	m = update(m, keys("esc")...)
+	if showsInfo(m) {
+		t.Error("Expected esc to close the notes")
+	}
+
+	// they were seen, so opening it again goes straight to the grid
+	puz, err := puzzle.LoadFromFile(m.State().Puzzle.Builder.Path())
+	if err != nil {
+		t.Fatalf("Failed to load puzzle: %v", err)
+	}
+	if !puz.Progress.NotesSeen {
+		t.Error("Expected the notes kept as seen across a reload")
+	}
+	model.OpenPuzzle(&m, puz)
+	if showsInfo(m) {
+		t.Error("Expected the notes shown only once")
+	}
+}
+
+func TestNotesNone(t *testing.T) {
+	m := openPuzzle(t, puzzleOptions{})
+	puz, err := puzzle.LoadFromFile(m.State().Puzzle.Builder.Path())
+	if err != nil {
+		t.Fatalf("Failed to load puzzle: %v", err)
+	}
+	puz.Notes = ""
+
+	model.OpenPuzzle(&m, puz)
+	if showsInfo(m) {
+		t.Error("Expected no info screen for a puzzle without notes")
+	}
+	// nothing to remember, so no progress is written
+	dir, _ := puzzle.ProgressDir()
+	if entries, _ := os.ReadDir(dir); puz.Progress.NotesSeen || len(entries) != 0 {
+		t.Errorf("Expected no progress kept, got seen=%v and %d files", puz.Progress.NotesSeen, len(entries))
+	}
+}
+
+func TestNotesSaveFailed(t *testing.T) {
+	m := openPuzzle(t, puzzleOptions{})
+	puz, err := puzzle.LoadFromFile(m.State().Puzzle.Builder.Path())
+	if err != nil {
+		t.Fatalf("Failed to load puzzle: %v", err)
+	}
+
+	// a file where the progress directory should be
+	data := filepath.Join(t.TempDir(), "data")
+	if err := os.WriteFile(data, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_DATA_HOME", data)
+
+	model.OpenPuzzle(&m, puz)
+	m = update(m, keys("esc")...)
+	if msg := m.State().PuzzleView.Message; !strings.HasPrefix(msg, "Saving progress failed: ") {
+		t.Errorf("Expected the failed save in the status bar, got %q", msg)
+	}
+}
@@ -0,0 +1,73 @@
+package puzzle_test
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/robertcurry0216/cross/internal/config"
+	"github.com/robertcurry0216/cross/internal/keymap"
+)
+
+func TestKeyMapOverrides(t *testing.T) {
+	cfg := config.Default()
+	err := config.Parse(&cfg, `
+[keys]
+check_word = "ctrl+o"
+toggle_direction = ["space", "ctrl+t"]
+delete = ["backspace", "ctrl+d"]
+reveal_puzzle = []
+`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	keys := keymap.New(cfg.Keys.Overrides())
+
+	ctrlO := tea.KeyMsg{Type: tea.KeyCtrlO}
+	ctrlW := tea.KeyMsg{Type: tea.KeyCtrlW}
+	if action, ok := keys.Action(ctrlO, keymap.PuzzleActions); !ok || action != keymap.CheckWord {
+		t.Errorf("Expected ctrl+o to check the word, got %v", action)
+	}
+	if _, ok := keys.Action(ctrlW, keymap.PuzzleActions); ok {
+		t.Errorf("Expected ctrl+w to be unbound")
+	}
+
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	if !keys.Matches(space, keymap.ToggleDirection) || !keys.Matches(tea.KeyMsg{Type: tea.KeyCtrlT}, keymap.ToggleDirection) {
+		t.Errorf("Expected space and ctrl+t to toggle direction")
+	}
+	if !keys.Matches(tea.KeyMsg{Type: tea.KeyBackspace}, keymap.Delete) {
+		t.Errorf("Expected backspace to delete")
+	}
+	if keys.HelpText(keymap.ToggleDirection, "") != "space/ctrl+t: Toggle direction" {
+		t.Errorf("Unexpected help %q", keys.HelpText(keymap.ToggleDirection, ""))
+	}
+
+	if keys.Binding(keymap.RevealPuzzle).Enabled() || keys.HelpText(keymap.RevealPuzzle, "") != "" {
+		t.Errorf("Expected reveal_puzzle to be unbound")
+	}
+	if keymap.JoinHelp("a: A", "", "b: B") != "a: A | b: B" {
+		t.Errorf("Expected unbound help to be skipped")
+	}
+}
+
+func TestKeyMapScreens(t *testing.T) {
+	keys := keymap.Default()
+	tab := tea.KeyMsg{Type: tea.KeyTab}
+
+	if action, _ := keys.Action(tab, keymap.PuzzleActions); action != keymap.NextClue {
+		t.Errorf("Expected tab to go to the next clue in the grid, got %v", action)
+	}
+	if action, _ := keys.Action(tab, keymap.ClueActions); action != keymap.NextUnsolved {
+		t.Errorf("Expected tab to go to the next unsolved clue, got %v", action)
+	}
+}
+
+func TestKeyMapUnknownAction(t *testing.T) {
+	cfg := config.Default()
+	err := config.Parse(&cfg, "[keys]\ncheck_word = \"ctrl+o\"\ncheck_everything = \"ctrl+x\"\n")
+
+	configErr, ok := err.(*config.Error)
+	if !ok || configErr.Line != 3 || configErr.Key != "keys.check_everything" {
+		t.Errorf("Expected an unknown action error on line 3, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package puzzle_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/robertcurry0216/cross/internal/config"
+	"github.com/robertcurry0216/cross/internal/model"
+)
+
+// lineOf returns the first line of the view with s in it, or -1.
+func lineOf(view, s string) int {
+	for i, line := range strings.Split(view, "\n") {
+		if strings.Contains(line, s) {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestLayoutBySize(t *testing.T) {
+	testCases := []struct {
+		name    string
+		layout  config.LayoutMode
+		w, h    int
+		want    []string
+		notWant []string
+	}{
+		{"columns", config.LayoutAuto, 100, 40, []string{"╭─Across", "╭─Down", "1 Across Shellfish"}, []string{"Clues"}},
+		{"side", config.LayoutAuto, 70, 40, []string{"╭─Clues", "Across:", "Down:"}, []string{"╭─Across"}},
+		{"stacked", config.LayoutAuto, 40, 40, []string{"╭─Clues", "Across:"}, nil},
+		{"bar", config.LayoutAuto, 40, 18, []string{"1 Across Shellfish"}, []string{"Clues", "Across:"}},
+		{"asked for side", config.LayoutSide, 100, 40, []string{"╭─Clues"}, []string{"╭─Across"}},
+		{"zen", config.LayoutZen, 100, 40, []string{"╭─Café Crossword"}, []string{"Clues", "Shellfish", "╭─Across"}},
+	}
+
+	for _, tc := range testCases {
+		view := openPuzzle(t, puzzleOptions{
+			config: func(cfg *config.Config) { cfg.Display.Layout = tc.layout },
+			width:  tc.w, height: tc.h,
+		}).View()
+
+		for _, want := range tc.want {
+			if !strings.Contains(view, want) {
+				t.Errorf("%s: expected %q in\n%s", tc.name, want, view)
+			}
+		}
+		for _, notWant := range tc.notWant {
+			if strings.Contains(view, notWant) {
+				t.Errorf("%s: didn't expect %q in\n%s", tc.name, notWant, view)
+			}
+		}
+
+		// the clues go beside the grid, or under it when stacked
+		clues, grid := lineOf(view, "╭─Clues"), lineOf(view, "╭─Café Crossword")
+		if tc.name == "side" && clues > grid {
+			t.Errorf("side: expected the clues beside the grid in\n%s", view)
+		}
+		if tc.name == "stacked" && clues <= grid {
+			t.Errorf("stacked: expected the clues under the grid in\n%s", view)
+		}
+	}
+}
+
+func TestGridFits(t *testing.T) {
+	testCases := []struct {
+		name    string
+		grid    config.GridMode
+		w, h    int
+		compact bool
+	}{
+		{"room for the full grid", config.GridAuto, 40, 18, false},
+		{"too small for the full grid", config.GridAuto, 20, 12, true},
+		{"full asked for", config.GridFull, 20, 12, false},
+		{"compact asked for", config.GridCompact, 100, 40, true},
+	}
+
+	for _, tc := range testCases {
+		view := openPuzzle(t, puzzleOptions{
+			config: func(cfg *config.Config) { cfg.Display.Grid = tc.grid },
+			width:  tc.w, height: tc.h,
+		}).View()
+
+		// the full grid has lines between the cells, compact has none
+		if compact := !strings.Contains(view, "┏"); compact != tc.compact {
+			t.Errorf("%s: expected compact %v in\n%s", tc.name, tc.compact, view)
+		}
+	}
+}
+
+func TestGridScrollsToCursor(t *testing.T) {
+	// the grids are smaller than the puzzle, so have to scroll
+	testCases := []struct {
+		grid config.GridMode
+		w, h int
+	}{
+		{config.GridFull, 14, 8},
+		{config.GridCompact, 6, 5},
+	}
+
+	for _, tc := range testCases {
+		m := openPuzzle(t, puzzleOptions{
+			config: func(cfg *config.Config) {
+				cfg.Display.Grid = tc.grid
+				cfg.Display.Layout = config.LayoutZen
+			},
+			width: tc.w, height: tc.h,
+		})
+		puz := m.State().Puzzle
+
+		// the far corner is out of view from the first cell
+		puz.CellAt(4, 4).SetInput("Z")
+		model.SelectCell(&m, puz.CellAt(0, 0), false)
+		if view := m.View(); strings.Contains(view, "Z") {
+			t.Errorf("%s: expected the last cell scrolled out of view in\n%s", tc.grid, view)
+		}
+		puz.CellAt(4, 4).Clear()
+
+		// and every cell comes into view when it is selected
+		for y := range puz.Height {
+			for x := range puz.Width {
+				cell := puz.CellAt(x, y)
+				if cell.IsBlank() {
+					continue
+				}
+				cell.SetInput("Q")
+				model.SelectCell(&m, cell, false)
+				if view := m.View(); !strings.Contains(view, "Q") {
+					t.Errorf("%s: expected (%d, %d) in view in\n%s", tc.grid, x, y, view)
+				}
+				cell.Clear()
+			}
+		}
+	}
+}
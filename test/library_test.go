@@ -0,0 +1,204 @@
+package puzzle_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/robertcurry0216/cross/internal/config"
+	"github.com/robertcurry0216/cross/internal/model"
+	"github.com/robertcurry0216/cross/internal/puzzle"
+	"github.com/robertcurry0216/cross/internal/theme"
+)
+
+func copyFixture(t *testing.T, name, dest string) {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(dest, raw, 0644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+}
+
+func TestScanLibrary(t *testing.T) {
+	dir := t.TempDir()
+	copyFixture(t, "test.puz", filepath.Join(dir, "a.puz"))
+	copyFixture(t, "utf8.puz", filepath.Join(dir, "sub", "b.puz"))
+	copyFixture(t, "test.puz", filepath.Join(dir, ".hidden", "c.puz"))
+	os.WriteFile(filepath.Join(dir, "broken.puz"), []byte("not a puzzle"), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("hello"), 0644)
+
+	entries := puzzle.ScanLibrary([]string{dir, dir})
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	titles := map[string]bool{}
+	for _, entry := range entries {
+		titles[entry.Title] = true
+		if entry.Width != 5 || entry.Height != 5 {
+			t.Errorf("Expected a 5x5 puzzle, got %dx%d", entry.Width, entry.Height)
+		}
+		if entry.Progress != 0 || entry.Solved {
+			t.Errorf("Expected %s to be untouched", entry.Title)
+		}
+	}
+	if !titles["Café Crossword"] || !titles["Über Puzzle ✓"] {
+		t.Errorf("Unexpected titles %v", titles)
+	}
+}
+
+func TestFilterAndSortLibrary(t *testing.T) {
+	now := time.Now()
+	entries := []*puzzle.LibraryEntry{
+		{Path: "b.puz", Title: "Beta", Author: "Zed", Date: now.Add(-time.Hour), Progress: 0.5},
+		{Path: "a.puz", Title: "alpha", Author: "Amy", Date: now.Add(-2 * time.Hour), Progress: 1},
+		{Path: "sunday.puz", Title: "Gamma", Author: "Bob", Date: now},
+	}
+
+	filtered := puzzle.FilterLibrary(entries, "AMY")
+	if len(filtered) != 1 || filtered[0].Title != "alpha" {
+		t.Errorf("Expected the filter to match the author")
+	}
+	if filtered := puzzle.FilterLibrary(entries, "sunday"); len(filtered) != 1 {
+		t.Errorf("Expected the filter to match the file name")
+	}
+
+	testCases := []struct {
+		by       puzzle.LibrarySort
+		expected []string
+	}{
+		{puzzle.SortByDate, []string{"Gamma", "Beta", "alpha"}},
+		{puzzle.SortByTitle, []string{"alpha", "Beta", "Gamma"}},
+		{puzzle.SortByAuthor, []string{"alpha", "Gamma", "Beta"}},
+		{puzzle.SortByProgress, []string{"alpha", "Beta", "Gamma"}},
+	}
+	for _, tc := range testCases {
+		puzzle.SortLibrary(entries, tc.by)
+		for i, title := range tc.expected {
+			if entries[i].Title != title {
+				t.Errorf("Sorting by %v: expected %s at %d, got %s", tc.by, title, i, entries[i].Title)
+			}
+		}
+	}
+}
+
+func TestLibraryEntryDate(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	dir := t.TempDir()
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		file string
+		date time.Time
+	}{
+		{"nyt-2024-03-01.puz", date},
+		{"Mar 1, 2024.puz", date},
+		{"March 1, 2024.puz", date},
+		{"2024-13-45.puz", time.Time{}},
+		{"3-1-2024.puz", time.Time{}},
+		{"sunday.puz", time.Time{}},
+	}
+
+	for _, tc := range testCases {
+		path := filepath.Join(dir, tc.file)
+		copyFixture(t, "test.puz", path)
+
+		entry, err := puzzle.NewLibraryEntry(path)
+		if err != nil {
+			t.Fatalf("%s: NewLibraryEntry failed: %v", tc.file, err)
+		}
+		if !entry.Date.Equal(tc.date) || entry.LastPlayed {
+			t.Errorf("%s: expected %v, got %v last played=%v", tc.file, tc.date, entry.Date, entry.LastPlayed)
+		}
+	}
+
+	// once it has been played, an undated puzzle has when that was
+	path := filepath.Join(dir, "sunday.puz")
+	puz, err := puzzle.LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load puzzle: %v", err)
+	}
+	puz.Progress.MetaAnswer = "COO"
+	if err := puz.SaveProgress(); err != nil {
+		t.Fatalf("Failed to save progress: %v", err)
+	}
+	progress, _ := puzzle.ProgressDir()
+	files, _ := os.ReadDir(progress)
+	played := time.Date(2026, 10, 1, 12, 0, 0, 0, time.Local)
+	if len(files) != 1 || os.Chtimes(filepath.Join(progress, files[0].Name()), played, played) != nil {
+		t.Fatalf("Expected a progress file, got %d", len(files))
+	}
+
+	entry, err := puzzle.NewLibraryEntry(path)
+	if err != nil {
+		t.Fatalf("NewLibraryEntry failed: %v", err)
+	}
+	if !entry.Date.Equal(played) || !entry.LastPlayed {
+		t.Errorf("Expected last played %v, got %v last played=%v", played, entry.Date, entry.LastPlayed)
+	}
+}
+
+func TestLibraryScansOnce(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	dir := t.TempDir()
+	copyFixture(t, "test.puz", filepath.Join(dir, "a.puz"))
+
+	cfg := config.Default()
+	cfg.General.Autosave = false
+	m := model.NewModel(cfg, theme.New("default", config.ColorsConfig{}, theme.Options{}))
+	model.OpenLibrary(&m, []string{dir})
+	m = update(m, tea.WindowSizeMsg{Width: 100, Height: 30})
+	if view := m.View(); !strings.Contains(view, "Library (1)") || strings.Contains(view, "last played ") {
+		t.Fatalf("Expected the puzzle, not yet played, in\n%s", view)
+	}
+
+	// a new file doesn't show until asked for, even after the help screen
+	copyFixture(t, "test.puz", filepath.Join(dir, "b.puz"))
+	m = update(m, keys("?", "esc")...)
+	if view := m.View(); !strings.Contains(view, "Library (1)") {
+		t.Errorf("Expected the library as scanned in\n%s", view)
+	}
+
+	// playing a puzzle updates its entry, and only its entry. The first esc
+	// closes the notes shown on first open.
+	m = update(m, keys("enter", "esc", "C", "esc")...)
+	if view := m.View(); !strings.Contains(view, "Library (1)") || !strings.Contains(view, " 4%") || !strings.Contains(view, "last played ") {
+		t.Errorf("Expected the played puzzle's progress in\n%s", view)
+	}
+
+	m = update(m, keys("r")...)
+	if view := m.View(); !strings.Contains(view, "Library (2)") {
+		t.Errorf("Expected r to rescan the library in\n%s", view)
+	}
+}
+
+func TestPuzzleCompletion(t *testing.T) {
+	puz := buildTestPuzzle(t)
+	if puz.Completion() != 0 || puz.IsSolved() {
+		t.Fatalf("Expected an empty puzzle")
+	}
+
+	for i, cell := range puz.Grid {
+		if !cell.IsBlank() && i < 5 {
+			cell.SetInput(cell.Solution)
+		}
+	}
+	if puz.Completion() != 4.0/23.0 {
+		t.Errorf("Expected 4/23 complete, got %v", puz.Completion())
+	}
+
+	for _, cell := range puz.Grid {
+		cell.SetInput(cell.Solution)
+	}
+	if puz.Completion() != 1 || !puz.IsSolved() {
+		t.Errorf("Expected a solved puzzle")
+	}
+}
@@ -0,0 +1,65 @@
+package puzzle_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/robertcurry0216/cross/common"
+	"github.com/robertcurry0216/cross/internal/puzzle"
+)
+
+func TestParseMarkup(t *testing.T) {
+	spans := puzzle.ParseMarkup("A <i>slanted</i> &amp; <b>bold <s>struck</s></b> H<sub>2</sub>O <font>x</font> a < b")
+
+	expected := []puzzle.Span{
+		{Text: "A ", Style: 0},
+		{Text: "slanted", Style: puzzle.SpanItalic},
+		{Text: " & ", Style: 0},
+		{Text: "bold ", Style: puzzle.SpanBold},
+		{Text: "struck", Style: puzzle.SpanBold | puzzle.SpanStrike},
+		{Text: " H", Style: 0},
+		{Text: "2", Style: puzzle.SpanSub},
+		{Text: "O x a < b", Style: 0},
+	}
+
+	if len(spans) != len(expected) {
+		t.Fatalf("Expected %d spans, got %d: %v", len(expected), len(spans), spans)
+	}
+	for i, span := range spans {
+		if span != expected[i] {
+			t.Errorf("Span %d = %+v, expected %+v", i, span, expected[i])
+		}
+	}
+
+	if plain := puzzle.StripMarkup("<I>Ciao</I>, &eacute;t&eacute;"); plain != "Ciao, été" {
+		t.Errorf("Expected plain text 'Ciao, été', got %q", plain)
+	}
+}
+
+func TestWrapStringStyled(t *testing.T) {
+	italic := "\x1b[3m"
+	reset := "\x1b[0m"
+	s := "plain " + italic + "one two three" + reset + " end"
+
+	wrapped := common.WrapString(s, 10)
+	lines := strings.Split(wrapped, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d: %q", len(lines), wrapped)
+	}
+
+	// escape sequences don't count towards the width
+	if plain := strings.Split(common.WrapString(strings.ReplaceAll(s, italic, ""), 10), "\n"); len(plain) != len(lines) {
+		t.Errorf("Styled text wrapped differently from plain text: %q", wrapped)
+	}
+
+	// styles are closed at the end of a line and reopened on the next
+	if !strings.HasSuffix(lines[0], reset) {
+		t.Errorf("Expected line 1 to end with a reset, got %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], italic) {
+		t.Errorf("Expected line 2 to reopen the style, got %q", lines[1])
+	}
+	if strings.HasPrefix(lines[2], italic) {
+		t.Errorf("Expected line 3 to be unstyled, got %q", lines[2])
+	}
+}
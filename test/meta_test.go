@@ -0,0 +1,92 @@
+package puzzle_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/robertcurry0216/cross/internal/puzzle"
+)
+
+func buildTestPuzzle(t *testing.T) *puzzle.Puzzle {
+	t.Helper()
+	builder, err := puzzle.NewBuilderFromFile(filepath.Join("testdata", "test.puz"))
+	if err != nil {
+		t.Fatalf("Failed to create builder: %v", err)
+	}
+	puz, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Failed to build puzzle: %v", err)
+	}
+	return puz
+}
+
+func TestCircledCells(t *testing.T) {
+	puz := buildTestPuzzle(t)
+
+	circled := puz.CircledCells()
+	if len(circled) != 3 || circled[0] != puz.CellAt(0, 0) || circled[2] != puz.CellAt(2, 2) {
+		t.Fatalf("Expected the 3 circled cells in grid order, got %d", len(circled))
+	}
+	if cells := puz.FindClue(5, false).CircledCells(); len(cells) != 1 || cells[0] != puz.CellAt(1, 1) {
+		t.Errorf("Expected 5 Across to have 1 circled cell, got %d", len(cells))
+	}
+}
+
+func TestMetaAnswerKept(t *testing.T) {
+	m := openPuzzle(t, puzzleOptions{filled: map[[2]int]string{{0, 0}: "C"}})
+	m = update(m, keys("ctrl+e", "c", "o", "o", "enter")...)
+
+	view := m.View()
+	if !strings.Contains(view, "C _ _") || !strings.Contains(view, "Meta answer: COO") {
+		t.Errorf("Expected the circled letters and the answer in\n%s", view)
+	}
+	// there is no solution to the meta to check it against
+	if strings.Contains(view, "match") {
+		t.Errorf("Expected no verdict on the answer in\n%s", view)
+	}
+
+	m = update(m, keys("esc")...)
+	if answer := m.State().Puzzle.Progress.MetaAnswer; answer != "COO" {
+		t.Errorf("Expected the answer kept, got %q", answer)
+	}
+}
+
+func TestProgressRoundTrip(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	puz := buildTestPuzzle(t)
+	puz.Progress.MetaAnswer = "COO"
+	if err := puz.SaveProgress(); err != nil {
+		t.Fatalf("Failed to save progress: %v", err)
+	}
+
+	puz2 := buildTestPuzzle(t)
+	if err := puz2.LoadProgress(); err != nil {
+		t.Fatalf("Failed to load progress: %v", err)
+	}
+	if puz2.Progress.MetaAnswer != "COO" {
+		t.Errorf("Expected meta answer COO, got %q", puz2.Progress.MetaAnswer)
+	}
+}
+
+func TestClueNotesProgress(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	puz := buildTestPuzzle(t)
+	puz.FindClue(7, false).Note = "SI SI?"
+	if err := puz.SaveProgress(); err != nil {
+		t.Fatalf("Failed to save progress: %v", err)
+	}
+
+	puz2 := buildTestPuzzle(t)
+	if err := puz2.LoadProgress(); err != nil {
+		t.Fatalf("Failed to load progress: %v", err)
+	}
+	if note := puz2.FindClue(7, false).Note; note != "SI SI?" {
+		t.Errorf("Expected note on 7-Across, got %q", note)
+	}
+	if note := puz2.FindClue(1, false).Note; note != "" {
+		t.Errorf("Expected no note on 1-Across, got %q", note)
+	}
+}
@@ -0,0 +1,151 @@
+package puzzle_test
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/robertcurry0216/cross/internal/config"
+	"github.com/robertcurry0216/cross/internal/model"
+)
+
+// screenPos finds where s is first drawn in the view, from line from on.
+func screenPos(t *testing.T, view, s string, from int) (int, int) {
+	t.Helper()
+	for y, line := range strings.Split(view, "\n") {
+		if i := strings.Index(line, s); i >= 0 && y >= from {
+			return ansi.StringWidth(line[:i]), y
+		}
+	}
+	t.Fatalf("Expected %q in\n%s", s, view)
+	return 0, 0
+}
+
+func mouse(x, y int, button tea.MouseButton) tea.MouseMsg {
+	return tea.MouseMsg{X: x, Y: y, Button: button, Action: tea.MouseActionPress}
+}
+
+// clickOn clicks where s is drawn, in the grid's box if inGrid is set
+// rather than in the clue bar over it.
+func clickOn(t *testing.T, m model.Model, s string, inGrid bool) model.Model {
+	t.Helper()
+	view := m.View()
+	from := 0
+	if inGrid {
+		_, from = screenPos(t, view, "╭─Caf", 0)
+	}
+	x, y := screenPos(t, view, s, from)
+	return update(m, mouse(x, y, tea.MouseButtonLeft))
+}
+
+func TestMouseSelectsCell(t *testing.T) {
+	testCases := []struct {
+		name   string
+		grid   config.GridMode
+		layout config.LayoutMode
+		w, h   int
+		// the cell selected before the click, so the grid scrolls to it
+		fromX, fromY int
+	}{
+		{"full", config.GridAuto, config.LayoutAuto, 100, 40, 0, 0},
+		{"compact", config.GridCompact, config.LayoutAuto, 100, 40, 0, 0},
+		{"scrolled", config.GridFull, config.LayoutZen, 14, 8, 4, 4},
+		{"compact scrolled", config.GridCompact, config.LayoutZen, 6, 5, 4, 4},
+	}
+
+	for _, tc := range testCases {
+		// a letter to find the cell by
+		m := openPuzzle(t, puzzleOptions{
+			config: func(cfg *config.Config) {
+				cfg.Display.Grid = tc.grid
+				cfg.Display.Layout = tc.layout
+			},
+			width: tc.w, height: tc.h,
+			filled: map[[2]int]string{{3, 3}: "Q"},
+			x:      tc.fromX, y: tc.fromY,
+		})
+		m = clickOn(t, m, "Q", true)
+		if view := m.State().PuzzleView; view.X != 3 || view.Y != 3 || view.IsVert {
+			t.Errorf("%s: expected (3, 3) across, got (%d, %d) vert=%v", tc.name, view.X, view.Y, view.IsVert)
+		}
+
+		// a second click on it goes down instead
+		m = clickOn(t, m, "Q", true)
+		if view := m.State().PuzzleView; view.X != 3 || view.Y != 3 || !view.IsVert {
+			t.Errorf("%s: expected a second click to go down, got (%d, %d) vert=%v", tc.name, view.X, view.Y, view.IsVert)
+		}
+	}
+}
+
+func TestMouseMissesCell(t *testing.T) {
+	m := openPuzzle(t, puzzleOptions{})
+
+	// the grid line to the right of the first cell, and the black square
+	// after 1 Across
+	x, y := screenPos(t, m.View(), "┏1━━┳2", 0)
+	for _, pos := range [][2]int{{x + 4, y + 1}, {x + 18, y + 1}} {
+		m = update(m, mouse(pos[0], pos[1], tea.MouseButtonLeft))
+		if view := m.State().PuzzleView; view.X != 0 || view.Y != 0 {
+			t.Errorf("Expected a click at (%d, %d) to leave the cursor, got (%d, %d)", pos[0], pos[1], view.X, view.Y)
+		}
+	}
+}
+
+func TestMouseSelectsClue(t *testing.T) {
+	m := openPuzzle(t, puzzleOptions{})
+
+	m = clickOn(t, m, "Stir up", false)
+	if view := m.State().PuzzleView; view.X != 0 || view.Y != 3 || view.IsVert {
+		t.Errorf("Expected 8 Across selected, got (%d, %d) vert=%v", view.X, view.Y, view.IsVert)
+	}
+
+	m = clickOn(t, m, "Leather", false)
+	if view := m.State().PuzzleView; view.X != 1 || view.Y != 0 || !view.IsVert {
+		t.Errorf("Expected 2 Down selected, got (%d, %d) vert=%v", view.X, view.Y, view.IsVert)
+	}
+}
+
+func TestMouseWheelScrollsClues(t *testing.T) {
+	// the clues are longer than their panel
+	m := openPuzzle(t, puzzleOptions{
+		config: func(cfg *config.Config) { cfg.Display.Layout = config.LayoutStacked },
+		width:  40, height: 24,
+	})
+	x, y := screenPos(t, m.View(), "╭─Clues", 0)
+	x, y = x+4, y+2
+	if strings.Contains(m.View(), "Leather") {
+		t.Fatalf("Expected the clues to need scrolling in\n%s", m.View())
+	}
+
+	m = update(m, mouse(x, y, tea.MouseButtonWheelDown))
+	if scroll := m.State().PuzzleView.ClueScroll; scroll != 3 {
+		t.Errorf("Expected the wheel to scroll the clues by 3, got %d", scroll)
+	}
+	if view := m.View(); !strings.Contains(view, "Leather") || strings.Contains(view, "Across:") {
+		t.Errorf("Expected the clues scrolled down in\n%s", view)
+	}
+
+	// it stops at the end, so going back up starts from there
+	m = update(m, mouse(x, y, tea.MouseButtonWheelDown), mouse(x, y, tea.MouseButtonWheelDown))
+	if view := m.View(); !strings.Contains(view, "Stir up") || !strings.Contains(view, "Not silently") {
+		t.Errorf("Expected the clues scrolled to the end in\n%s", view)
+	}
+	m = update(m, mouse(x, y, tea.MouseButtonWheelUp))
+	if view := m.View(); !strings.Contains(view, "Shellfish") || strings.Contains(view, "Not silently") {
+		t.Errorf("Expected the clues scrolled back up in\n%s", view)
+	}
+
+	// the wheel over the grid does nothing, and a key brings the selected
+	// clue back
+	before := m.State().PuzzleView.ClueScroll
+	gx, gy := screenPos(t, m.View(), "****", 0)
+	m = update(m, mouse(gx, gy, tea.MouseButtonWheelDown))
+	if scroll := m.State().PuzzleView.ClueScroll; scroll != before {
+		t.Errorf("Expected the wheel over the grid to leave the clues, got %d from %d", scroll, before)
+	}
+	m = update(m, keys("right")...)
+	if view := m.View(); !strings.Contains(view, "Across:") {
+		t.Errorf("Expected a key to scroll back to the selected clue in\n%s", view)
+	}
+}
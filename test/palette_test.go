@@ -0,0 +1,135 @@
+package puzzle_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+	"github.com/robertcurry0216/cross/internal/config"
+	"github.com/robertcurry0216/cross/internal/model"
+	"github.com/robertcurry0216/cross/internal/puzzle"
+	"github.com/robertcurry0216/cross/internal/screen"
+)
+
+// openPalette opens test.puz and the command palette over it, with query
+// typed in.
+func openPalette(t *testing.T, query string) (model.Model, *screen.PaletteScreen) {
+	t.Helper()
+	m := update(openPuzzle(t, puzzleOptions{}), keys("ctrl+k")...)
+	for _, r := range query {
+		m = update(m, keys(string(r))...)
+	}
+
+	views := m.State().Views
+	palette, ok := views[len(views)-1].(*screen.PaletteScreen)
+	if !ok {
+		t.Fatalf("Expected ctrl+k to open the palette, got %T", views[len(views)-1])
+	}
+	return m, palette
+}
+
+func TestPaletteItems(t *testing.T) {
+	testCases := []struct {
+		query string
+		// the titles expected first, in order
+		want []string
+	}{
+		{"cw", []string{"Check word"}},
+		{"check", []string{"Check letter", "Check word", "Check puzzle"}},
+		{"3d", []string{"3 Down: Ancient kingdom"}},
+		{"6 down", []string{"6 Down: Not silently"}},
+		{"1", []string{"1 Across: Shellfish", "1 Down: Burn"}},
+		{"1a", []string{"1 Across: Shellfish"}},
+		{"prophet", []string{"5 Across: Minor prophet", "4 Down: Minor prophet"}},
+		{"ancient kingdom", []string{"3 Down: Ancient kingdom"}},
+	}
+
+	for _, tc := range testCases {
+		m, palette := openPalette(t, tc.query)
+		items := palette.Items(m.State())
+		if len(items) < len(tc.want) {
+			t.Errorf("%s: expected at least %d items, got %d", tc.query, len(tc.want), len(items))
+			continue
+		}
+		for i, want := range tc.want {
+			if items[i].Title != want {
+				t.Errorf("%s: expected %q at %d, got %q", tc.query, want, i, items[i].Title)
+			}
+		}
+	}
+
+	// clues that don't exist, and words in no clue, find nothing
+	for _, query := range []string{"12a", "7d", "zebra"} {
+		m, palette := openPalette(t, query)
+		for _, item := range palette.Items(m.State()) {
+			if item.Clue != nil {
+				t.Errorf("%s: didn't expect clue %q", query, item.Title)
+			}
+		}
+	}
+}
+
+func TestPaletteRuns(t *testing.T) {
+	m, _ := openPalette(t, "7a")
+	m = update(m, keys("enter")...)
+	if view := m.State().PuzzleView; len(m.State().Views) != 1 || view.X != 0 || view.Y != 2 || view.IsVert {
+		t.Errorf("Expected enter to close the palette on 7 Across, got (%d, %d) vert=%v", view.X, view.Y, view.IsVert)
+	}
+
+	m, _ = openPalette(t, "export")
+	m = update(m, keys("enter")...)
+	puz := m.State().Puzzle
+	path := strings.TrimSuffix(puz.Builder.Path(), ".puz") + ".txt"
+	if raw, err := os.ReadFile(path); err != nil || string(raw) != puzzle.PuzzleText(puz) {
+		t.Errorf("Expected the puzzle exported to %s, got %v", path, err)
+	}
+	if msg := m.State().PuzzleView.Message; msg != "Exported to "+path {
+		t.Errorf("Expected the export's path in the status bar, got %q", msg)
+	}
+}
+
+func TestPaletteRows(t *testing.T) {
+	// draw the highlight, as a color terminal would
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI)
+	t.Cleanup(func() { lipgloss.SetColorProfile(profile) })
+
+	m, _ := openPalette(t, "check")
+	lines := strings.Split(ansi.Strip(m.View()), "\n")
+	selected, next := lineOf(strings.Join(lines, "\n"), "Check letter"), lineOf(strings.Join(lines, "\n"), "Check word")
+	if selected < 0 || next < 0 {
+		t.Fatalf("Expected the check actions in\n%s", strings.Join(lines, "\n"))
+	}
+
+	// the selected row's hint lines up with the rest
+	if a, b := lipgloss.Width(lines[selected]), lipgloss.Width(lines[next]); a != b {
+		t.Errorf("Expected the rows the same width, got %d and %d:\n%s\n%s", a, b, lines[selected], lines[next])
+	}
+	if a, b := strings.LastIndex(lines[selected], "ctrl+l"), strings.LastIndex(lines[next], "ctrl+"); a < 0 || a != b {
+		t.Errorf("Expected the hints lined up:\n%s\n%s", lines[selected], lines[next])
+	}
+}
+
+func TestPaletteRebound(t *testing.T) {
+	m := openPuzzle(t, puzzleOptions{config: func(cfg *config.Config) {
+		cfg.Keys = config.KeysConfig{"back": {"ctrl+g"}, "down": {"ctrl+w"}}
+	}})
+	m = update(m, keys("ctrl+k")...)
+	if view := m.View(); !strings.Contains(view, "ctrl+w: Next") || !strings.Contains(view, "ctrl+g: Close") {
+		t.Errorf("Expected the rebound keys in the palette's help in\n%s", view)
+	}
+
+	m = update(m, keys("ctrl+w", "esc")...)
+	views := m.State().Views
+	palette, ok := views[len(views)-1].(*screen.PaletteScreen)
+	if !ok || palette.Selected != 1 {
+		t.Fatalf("Expected the rebound key to select the next item, and esc to do nothing")
+	}
+	m = update(m, keys("ctrl+g")...)
+	if len(m.State().Views) != 1 {
+		t.Errorf("Expected the rebound back key to close the palette")
+	}
+}
@@ -0,0 +1,140 @@
+package puzzle_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+	"github.com/robertcurry0216/cross/internal/config"
+	"github.com/robertcurry0216/cross/internal/theme"
+)
+
+func TestRenderCharsets(t *testing.T) {
+	testCases := []struct {
+		ascii   bool
+		want    []string
+		notWant string
+	}{
+		{false, []string{"╭─Café Crossword", "┳2━━┳3", "┃○C ┃"}, "+-"},
+		{true, []string{"+-Café Crossword", "+2--+3", "|oC |"}, "┃"},
+	}
+
+	for _, tc := range testCases {
+		m := openPuzzle(t, puzzleOptions{theme: theme.Options{ASCII: tc.ascii}})
+		m = update(m, keys("C")...)
+
+		view := m.View()
+		for _, want := range tc.want {
+			if !strings.Contains(view, want) {
+				t.Errorf("ascii=%v: expected %q in\n%s", tc.ascii, want, view)
+			}
+		}
+		if strings.Contains(view, tc.notWant) {
+			t.Errorf("ascii=%v: didn't expect %q in\n%s", tc.ascii, tc.notWant, view)
+		}
+
+		// the info screen draws with the same characters
+		info := update(m, keys("ctrl+g")...).View()
+		if size := "Size:    5" + map[bool]string{false: "×", true: "x"}[tc.ascii] + "5"; !strings.Contains(info, size) {
+			t.Errorf("ascii=%v: expected %q in\n%s", tc.ascii, size, info)
+		}
+	}
+}
+
+func TestRenderCellStyles(t *testing.T) {
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI)
+	t.Cleanup(func() { lipgloss.SetColorProfile(profile) })
+
+	colors := config.ColorsConfig{
+		Shaded:  config.Color{Light: "8", Dark: "8"},
+		Correct: config.Color{Light: "2", Dark: "2"},
+		Error:   config.Color{Light: "1", Dark: "1"},
+	}
+	testCases := []struct {
+		ascii        bool
+		right, wrong string
+	}{
+		{false, "○C✓", " X╱"},
+		{true, "oC+", " X/"},
+	}
+	for _, tc := range testCases {
+		m := openPuzzle(t, puzzleOptions{theme: theme.Options{ASCII: tc.ascii}, colors: colors})
+		puz := m.State().Puzzle
+		puz.CellAt(2, 3).Style.Shaded = true
+		puz.CellAt(3, 3).Style.Color = "4"
+		m = update(m, keys("C", "X", "ctrl+w")...)
+		view := m.View()
+
+		// the ASCII grid is drawn like the box drawing one, only its lines differ
+		for _, want := range []string{
+			"\x1b[1;32m" + tc.right + "\x1b[0m",
+			"\x1b[1;31m" + tc.wrong + "\x1b[0m",
+			"\x1b[100m . \x1b[0m",
+			"\x1b[44m . \x1b[0m",
+		} {
+			if !strings.Contains(view, want) {
+				t.Errorf("ascii=%v: expected %q in\n%s", tc.ascii, want, view)
+			}
+		}
+	}
+}
+
+func TestRenderCircleLongNumber(t *testing.T) {
+	for _, ascii := range []bool{false, true} {
+		m := openPuzzle(t, puzzleOptions{theme: theme.Options{ASCII: ascii}})
+		cell := m.State().Puzzle.CellAt(0, 0)
+		cell.ClueHoriz.Number, cell.ClueVert.Number = 100, 100
+
+		// the number fills the line above, and the circle is in the cell
+		view := ansi.Strip(m.View())
+		want := map[bool][]string{false: {"┏100┳", "┃○* ┃"}, true: {"+100+", "|o* |"}}[ascii]
+		for _, want := range want {
+			if !strings.Contains(view, want) {
+				t.Errorf("ascii=%v: expected %q in\n%s", ascii, want, view)
+			}
+		}
+	}
+}
+
+func TestRenderCheckedMono(t *testing.T) {
+	// draw the attributes, as cross does without colors
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI)
+	t.Cleanup(func() { lipgloss.SetColorProfile(profile) })
+
+	testCases := []struct {
+		ascii        bool
+		right, wrong string
+	}{
+		{false, "C✓", "X╱"},
+		{true, "C+", "X/"},
+	}
+	for _, tc := range testCases {
+		m := openPuzzle(t, puzzleOptions{theme: theme.Options{Mono: true, ASCII: tc.ascii}})
+		m = update(m, keys("C", "X", "ctrl+w")...)
+
+		view := ansi.Strip(m.View())
+		for _, want := range []string{tc.right, tc.wrong} {
+			if !strings.Contains(view, want) {
+				t.Errorf("ascii=%v: expected %q in\n%s", tc.ascii, want, view)
+			}
+		}
+	}
+
+	// compact has no room for the marks, so checked letters look different
+	m := openPuzzle(t, puzzleOptions{
+		config: func(cfg *config.Config) { cfg.Display.Grid = config.GridCompact },
+		theme:  theme.Options{Mono: true},
+	})
+	m = update(m, keys("C", "X")...)
+	letterC := regexp.MustCompile("\x1b\\[([0-9;]*)mC\x1b\\[0m")
+	before := letterC.FindStringSubmatch(m.View())
+	after := letterC.FindStringSubmatch(update(m, keys("ctrl+w")...).View())
+	if before == nil || after == nil || before[1] == after[1] {
+		t.Errorf("Expected a checked letter in compact to look unlike an unchecked one, got %q and %q", before, after)
+	}
+}
@@ -0,0 +1,132 @@
+package puzzle_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/robertcurry0216/cross/internal/config"
+	"github.com/robertcurry0216/cross/internal/theme"
+)
+
+func TestLoadTheme(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "dusk.toml"), []byte(`
+bright = true
+
+[colors]
+error = "#ff0000"
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Default()
+	if err := config.Parse(&cfg, "[display]\ntheme = \"dusk\"\n\n[colors]\ncorrect = \"3\"\nshaded = { dark = \"236\" }\n"); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	th, err := theme.Load(cfg, dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if th.Palette.Error.Light != "#ff0000" {
+		t.Errorf("Expected the theme file's error color, got %+v", th.Palette.Error)
+	}
+	if th.Palette.Correct.Dark != "3" {
+		t.Errorf("Expected the config's colors over the theme's, got %+v", th.Palette.Correct)
+	}
+	if th.Palette.Shaded.Dark != "236" || th.Palette.Shaded.Light != "253" {
+		t.Errorf("Expected a dark color alone to keep the theme's light one, got %+v", th.Palette.Shaded)
+	}
+	if !th.Palette.GridLine.IsSet() {
+		t.Errorf("Expected colors the theme leaves out to come from the default theme")
+	}
+}
+
+func TestBuiltinThemes(t *testing.T) {
+	for _, name := range theme.Names() {
+		cfg := config.Default()
+		cfg.Display.Theme = name
+		th, err := theme.Load(cfg, t.TempDir())
+		if err != nil {
+			t.Fatalf("%s: Load failed: %v", name, err)
+		}
+		if th.Palette.Correct == th.Palette.Error {
+			t.Errorf("%s: Expected right and wrong letters in different colors", name)
+		}
+	}
+}
+
+func TestLoadThemeErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "bad.toml"), []byte("[colours]\nerror = \"1\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Default()
+	cfg.Display.Theme = "missing"
+	if _, err := theme.Load(cfg, dir); err == nil {
+		t.Errorf("Expected an unknown theme to fail")
+	}
+
+	cfg.Display.Theme = "bad"
+	_, err := theme.Load(cfg, dir)
+	var configErr *config.Error
+	if !errors.As(err, &configErr) || configErr.Key != "colours" {
+		t.Errorf("Expected an unknown setting in the theme file, got %v", err)
+	}
+}
+
+func TestThemeNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	for _, name := range theme.Names() {
+		cfg := config.Default()
+		cfg.Display.Theme = name
+		th, err := theme.Load(cfg, t.TempDir())
+		if err != nil {
+			t.Fatalf("%s: Load failed: %v", name, err)
+		}
+		if !th.Mono {
+			t.Errorf("%s: Expected NO_COLOR to drop the colors", name)
+		}
+	}
+
+	th := theme.New("default", config.ColorsConfig{}, theme.Options{Mono: true})
+	if !th.HighlightCell.GetReverse() {
+		t.Errorf("Expected the selected cell in reverse video without colors")
+	}
+}
+
+func TestThemeCharset(t *testing.T) {
+	testCases := []struct {
+		charset config.Charset
+		lcAll   string
+		lang    string
+		ascii   bool
+	}{
+		{config.CharsetAuto, "", "en_US.UTF-8", false},
+		{config.CharsetAuto, "", "", false},
+		{config.CharsetAuto, "C", "en_US.UTF-8", true},
+		{config.CharsetAuto, "", "de_DE.ISO-8859-1", true},
+		{config.CharsetUnicode, "C", "", false},
+		{config.CharsetASCII, "", "en_US.utf8", true},
+	}
+
+	for _, tc := range testCases {
+		t.Setenv("LC_ALL", tc.lcAll)
+		t.Setenv("LC_CTYPE", "")
+		t.Setenv("LANG", tc.lang)
+
+		cfg := config.Default()
+		cfg.Display.Charset = tc.charset
+		th, err := theme.Load(cfg, t.TempDir())
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if th.ASCII != tc.ascii {
+			t.Errorf("%s with LC_ALL=%q LANG=%q: expected ASCII %v", tc.charset, tc.lcAll, tc.lang, tc.ascii)
+		}
+	}
+}
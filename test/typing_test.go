@@ -0,0 +1,95 @@
+package puzzle_test
+
+import (
+	"testing"
+
+	"github.com/robertcurry0216/cross/internal/config"
+)
+
+// fillRow fills the row's cells from x to x+len(letters).
+func fillRow(filled map[[2]int]string, x, y int, letters string) map[[2]int]string {
+	for i, r := range letters {
+		filled[[2]int{x + i, y}] = string(r)
+	}
+	return filled
+}
+
+func TestTypingMovesOn(t *testing.T) {
+	all := func(c *config.TypingConfig) {
+		c.SkipFilled, c.JumpToNextClue, c.WrapDirections = true, true, true
+	}
+	allBut := func(off func(*config.TypingConfig)) func(*config.TypingConfig) {
+		return func(c *config.TypingConfig) {
+			all(c)
+			off(c)
+		}
+	}
+	testCases := []struct {
+		name   string
+		set    func(*config.TypingConfig)
+		filled map[[2]int]string
+		x, y   int
+		typed  string
+		wantX  int
+		wantY  int
+		isVert bool
+	}{
+		{"the next cell by default", func(*config.TypingConfig) {},
+			fillRow(map[[2]int]string{}, 1, 0, "R"), 0, 0, "C", 1, 0, false},
+		{"skips filled", all, fillRow(map[[2]int]string{}, 1, 0, "R"), 0, 0, "C", 2, 0, false},
+		{"doesn't skip filled", allBut(func(c *config.TypingConfig) { c.SkipFilled = false }),
+			fillRow(map[[2]int]string{}, 1, 0, "R"), 0, 0, "C", 1, 0, false},
+		{"back to a gap", all, fillRow(map[[2]int]string{}, 1, 0, "RA"), 3, 0, "B", 0, 0, false},
+		{"jumps to the next unfinished answer", all,
+			fillRow(fillRow(map[[2]int]string{}, 0, 0, "CRA"), 0, 1, "HOSEA"), 3, 0, "B", 0, 2, false},
+		// stays at the end of the answer, with the black square after it
+		{"doesn't jump", allBut(func(c *config.TypingConfig) { c.JumpToNextClue = false }),
+			fillRow(fillRow(map[[2]int]string{}, 0, 0, "CRA"), 0, 1, "HOSEA"), 3, 0, "B", 3, 0, false},
+		{"wraps to Down", all, fillRow(map[[2]int]string{}, 1, 4, "SNO"), 4, 4, "B", 0, 0, true},
+		{"doesn't wrap", allBut(func(c *config.TypingConfig) { c.WrapDirections = false }),
+			fillRow(map[[2]int]string{}, 1, 4, "SNO"), 4, 4, "B", 0, 0, false},
+	}
+
+	for _, tc := range testCases {
+		m := openPuzzle(t, puzzleOptions{
+			config: func(cfg *config.Config) { tc.set(&cfg.Typing) },
+			filled: tc.filled,
+			x:      tc.x, y: tc.y,
+		})
+		m = update(m, keys(tc.typed)...)
+
+		view := m.State().PuzzleView
+		if got := inputAt(m, tc.x, tc.y); got != tc.typed {
+			t.Errorf("%s: expected %q typed at (%d, %d), got %q", tc.name, tc.typed, tc.x, tc.y, got)
+		}
+		if view.X != tc.wantX || view.Y != tc.wantY || view.IsVert != tc.isVert {
+			t.Errorf("%s: expected (%d, %d) vert=%v, got (%d, %d) vert=%v", tc.name, tc.wantX, tc.wantY, tc.isVert, view.X, view.Y, view.IsVert)
+		}
+	}
+}
+
+func TestArrowChangesDirection(t *testing.T) {
+	testCases := []struct {
+		name   string
+		on     bool
+		wantY  int
+		isVert bool
+	}{
+		{"turns", true, 1, true},
+		{"moves", false, 2, false},
+	}
+
+	for _, tc := range testCases {
+		// starts on the H of 5 Across
+		m := openPuzzle(t, puzzleOptions{
+			config: func(cfg *config.Config) { cfg.Typing.ArrowChangesDirection = tc.on },
+			x:      0, y: 1,
+		})
+		m = update(m, keys("down")...)
+
+		view := m.State().PuzzleView
+		if view.X != 0 || view.Y != tc.wantY || view.IsVert != tc.isVert {
+			t.Errorf("%s: expected (0, %d) vert=%v, got (%d, %d) vert=%v", tc.name, tc.wantY, tc.isVert, view.X, view.Y, view.IsVert)
+		}
+	}
+}
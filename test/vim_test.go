@@ -0,0 +1,127 @@
+package puzzle_test
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/robertcurry0216/cross/common"
+	"github.com/robertcurry0216/cross/internal/config"
+	"github.com/robertcurry0216/cross/internal/model"
+)
+
+func openVimPuzzle(t *testing.T) model.Model {
+	t.Helper()
+	return openPuzzle(t, puzzleOptions{config: func(cfg *config.Config) { cfg.General.Input = config.InputVim }})
+}
+
+func TestVimMotions(t *testing.T) {
+	testCases := []struct {
+		name   string
+		keys   []string
+		x, y   int
+		isVert bool
+	}{
+		{"count", []string{"3", "l"}, 3, 0, false},
+		{"count past the edge", []string{"9", "l"}, 3, 0, false},
+		{"down", []string{"j", "j"}, 0, 2, false},
+		{"end of word", []string{"$"}, 3, 0, false},
+		{"start of word", []string{"$", "0"}, 0, 0, false},
+		{"next clues", []string{"2", "w"}, 0, 2, false},
+		{"last clue", []string{"G"}, 1, 4, false},
+		{"clue by count", []string{"8", "G"}, 0, 3, false},
+		{"first clue", []string{"G", "g", "g"}, 0, 0, false},
+		{"clue by count with gg", []string{"7", "g", "g"}, 0, 2, false},
+		{"missing clue", []string{"1", "2", "g", "g"}, 0, 0, false},
+		{"go to clue", []string{":", "7", "a", "enter"}, 0, 2, false},
+		{"go to down clue", []string{":", "6", "d", "o", "w", "n", "enter"}, 4, 1, true},
+	}
+
+	for _, tc := range testCases {
+		m := update(openVimPuzzle(t), keys(tc.keys...)...)
+		view := m.State().PuzzleView
+		if view.X != tc.x || view.Y != tc.y || view.IsVert != tc.isVert {
+			t.Errorf("%s: expected (%d, %d) vert=%v, got (%d, %d) vert=%v", tc.name, tc.x, tc.y, tc.isVert, view.X, view.Y, view.IsVert)
+		}
+		if view.Vim.Mode != common.VimNormal {
+			t.Errorf("%s: expected to end in normal mode, got %v", tc.name, view.Vim.Mode)
+		}
+	}
+}
+
+func TestVimModes(t *testing.T) {
+	m := openVimPuzzle(t)
+
+	// letters don't type in normal mode
+	m = update(m, keys("C")...)
+	if inputAt(m, 0, 0) != "" {
+		t.Errorf("Expected normal mode not to type, got %q", inputAt(m, 0, 0))
+	}
+
+	m = update(m, keys("i", "C", "R", "A")...)
+	if mode := m.State().PuzzleView.Vim.Mode; mode != common.VimInsert {
+		t.Fatalf("Expected insert mode, got %v", mode)
+	}
+	if inputAt(m, 0, 0) != "C" || inputAt(m, 2, 0) != "A" {
+		t.Errorf("Expected CRA typed in insert mode, got %q..%q", inputAt(m, 0, 0), inputAt(m, 2, 0))
+	}
+
+	m = update(m, keys("esc")...)
+	if mode := m.State().PuzzleView.Vim.Mode; mode != common.VimNormal {
+		t.Fatalf("Expected esc to go back to normal mode, got %v", mode)
+	}
+	if len(m.State().Views) != 1 {
+		t.Errorf("Expected esc to leave the puzzle open")
+	}
+
+	// x clears a letter and moves on with a count, r replaces one in place
+	m = update(m, keys("0", "2", "x")...)
+	if inputAt(m, 0, 0) != "" || inputAt(m, 1, 0) != "" || inputAt(m, 2, 0) != "A" {
+		t.Errorf("Expected 2x to clear the first two letters, got %q %q %q", inputAt(m, 0, 0), inputAt(m, 1, 0), inputAt(m, 2, 0))
+	}
+	m = update(m, keys("l", "r", "Z")...)
+	if view := m.State().PuzzleView; inputAt(m, view.X, view.Y) != "Z" || view.Vim.Mode != common.VimNormal {
+		t.Errorf("Expected r to replace the letter under the cursor and stay in normal mode")
+	}
+
+	// a appends after the cursor
+	m = update(m, keys("0", "a", "Q", "esc")...)
+	if inputAt(m, 1, 0) != "Q" {
+		t.Errorf("Expected a to insert after the cursor, got %q", inputAt(m, 1, 0))
+	}
+
+	// esc and backspace leave command mode
+	for _, leave := range []string{"esc", "backspace"} {
+		m = update(m, keys(":")...)
+		if mode := m.State().PuzzleView.Vim.Mode; mode != common.VimCommand {
+			t.Fatalf("Expected : to start a command, got %v", mode)
+		}
+		m = update(m, keys(leave)...)
+		if mode := m.State().PuzzleView.Vim.Mode; mode != common.VimNormal {
+			t.Errorf("Expected %s to leave command mode, got %v", leave, mode)
+		}
+	}
+}
+
+func TestVimCommands(t *testing.T) {
+	m := update(openVimPuzzle(t), keys(":", "1", "2", "a", "enter")...)
+	if msg := m.State().PuzzleView.Message; msg != "No clue 12A" {
+		t.Errorf("Expected a missing clue message, got %q", msg)
+	}
+
+	m = update(m, keys(":", "f", "o", "o", "enter")...)
+	if msg := m.State().PuzzleView.Message; msg != "Not a command: foo" {
+		t.Errorf("Expected an unknown command message, got %q", msg)
+	}
+
+	m = update(m, keys(":", "w", "enter")...)
+	if msg := m.State().PuzzleView.Message; msg != "Saved" {
+		t.Errorf("Expected :w to save, got %q", msg)
+	}
+
+	// :q closes the puzzle, the last view, and so quits
+	m = update(m, keys(":", "q")...)
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(next.(model.Model).State().Views) != 0 || cmd == nil {
+		t.Errorf("Expected :q to close the puzzle and quit")
+	}
+}